@@ -0,0 +1,112 @@
+package network
+
+import (
+	"fmt"
+	"net"
+)
+
+type addressSpace struct {
+	network     *net.IPNet
+	description string
+}
+
+var (
+	// specialAddressSpaces can't be used for subnetworks in gcp or are reserved by gke
+	specialAddressSpaces = []addressSpace{
+		newAddressSpace("0.0.0.0/8", "the current network"),
+		newAddressSpace("127.0.0.0/8", "loopback addresses"),
+		newAddressSpace("169.254.0.0/16", "link-local addresses, used for the metadata server"),
+		newAddressSpace("224.0.0.0/4", "multicast addresses"),
+		newAddressSpace("172.17.0.0/16", "the docker bridge network on gke nodes"),
+	}
+
+	// privateAddressSpaces are the rfc 1918 ranges
+	privateAddressSpaces = []addressSpace{
+		newAddressSpace("10.0.0.0/8", "rfc 1918"),
+		newAddressSpace("172.16.0.0/12", "rfc 1918"),
+		newAddressSpace("192.168.0.0/16", "rfc 1918"),
+	}
+
+	// nonPublicAddressSpaces are not rfc 1918, but gcp allows them for subnetworks without them being public addresses
+	nonPublicAddressSpaces = []addressSpace{
+		newAddressSpace("100.64.0.0/10", "shared address space (rfc 6598)"),
+		newAddressSpace("192.0.0.0/24", "ietf protocol assignments (rfc 6890)"),
+		newAddressSpace("192.0.2.0/24", "documentation (rfc 5737)"),
+		newAddressSpace("198.18.0.0/15", "benchmarking (rfc 2544)"),
+		newAddressSpace("198.51.100.0/24", "documentation (rfc 5737)"),
+		newAddressSpace("203.0.113.0/24", "documentation (rfc 5737)"),
+		newAddressSpace("240.0.0.0/4", "reserved for future use (rfc 1112)"),
+	}
+
+	// gkeMaxSubnetMasks are the smallest ranges per type gke supports; master ranges need to be exactly a /28
+	gkeMaxSubnetMasks = map[Type]int{
+		TypeNode:    29,
+		TypePod:     24,
+		TypeService: 27,
+		TypeMaster:  28,
+	}
+)
+
+func newAddressSpace(cidr, description string) addressSpace {
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return addressSpace{network: ipnet, description: description}
+}
+
+func (as addressSpace) overlaps(ipnet *net.IPNet) bool {
+	return as.network.Contains(ipnet.IP) || ipnet.Contains(as.network.IP)
+}
+
+func (as addressSpace) contains(ipnet *net.IPNet) bool {
+	ones, _ := ipnet.Mask.Size()
+	asOnes, _ := as.network.Mask.Size()
+	return as.network.Contains(ipnet.IP) && ones >= asOnes
+}
+
+// validateAddressSpace warns about ipv4 networks gcp or gke can't use or treat specially, and about subnet masks too small for gke
+func (rc *RangeConfig) validateAddressSpace(ipnet *net.IPNet) (warnings []fieldMessage) {
+
+	if ipnet.IP.To4() == nil {
+		return
+	}
+
+	for _, as := range specialAddressSpaces {
+		if as.overlaps(ipnet) {
+			warnings = append(warnings, fieldMessage{"network", fmt.Sprintf("Value for field network overlaps with %v, which is reserved for %v", as.network, as.description)})
+		}
+	}
+
+	isPrivate := false
+	for _, as := range privateAddressSpaces {
+		if as.contains(ipnet) {
+			isPrivate = true
+		}
+	}
+
+	if !isPrivate {
+		isNonPublic := false
+		for _, as := range nonPublicAddressSpaces {
+			if as.contains(ipnet) {
+				isNonPublic = true
+				warnings = append(warnings, fieldMessage{"network", fmt.Sprintf("Value for field network is not in rfc 1918 address space but in %v for %v; make sure all peered networks and on-premise networks can route it", as.network, as.description)})
+			}
+		}
+		if !isNonPublic {
+			warnings = append(warnings, fieldMessage{"network", "Value for field network is not in rfc 1918 address space and will be used as privately used public address space; gke clusters need to be configured to allow this and the public services in this range become unreachable"})
+		}
+	}
+
+	if maxSubnetMask, ok := gkeMaxSubnetMasks[rc.Type]; ok {
+		if rc.Type == TypeMaster && rc.SubnetMask != maxSubnetMask {
+			warnings = append(warnings, fieldMessage{"subnet_mask", fmt.Sprintf("Value for field subnet_mask is %v, but gke requires master ranges to be exactly a /%v", rc.SubnetMask, maxSubnetMask)})
+		} else if rc.SubnetMask > maxSubnetMask {
+			warnings = append(warnings, fieldMessage{"subnet_mask", fmt.Sprintf("Value for field subnet_mask is %v, which is smaller than the minimum gke supports for type %v; please set to %v or less", rc.SubnetMask, rc.Type, maxSubnetMask)})
+		} else if rc.MaxSubnetMask > maxSubnetMask {
+			warnings = append(warnings, fieldMessage{"max_subnet_mask", fmt.Sprintf("Value for field max_subnet_mask is %v, which is smaller than the minimum gke supports for type %v; please set to %v or less", rc.MaxSubnetMask, rc.Type, maxSubnetMask)})
+		}
+	}
+
+	return
+}
@@ -0,0 +1,69 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AllocationStrategy determines which of the free candidate ranges of a range config is suggested
+type AllocationStrategy string
+
+const (
+	// AllocationStrategyFirstFit suggests the lowest free candidate
+	AllocationStrategyFirstFit AllocationStrategy = "first-fit"
+	// AllocationStrategyBestFit suggests a candidate from the smallest free block it fits in, so larger free blocks are kept for larger ranges
+	AllocationStrategyBestFit AllocationStrategy = "best-fit"
+	// AllocationStrategyLastFit suggests the highest free candidate, allocating top-down
+	AllocationStrategyLastFit AllocationStrategy = "last-fit"
+
+	// AllocationStrategyDefault leaves the choice to the range config, which defaults to first-fit
+	AllocationStrategyDefault AllocationStrategy = ""
+)
+
+var (
+	allocationStrategies = []AllocationStrategy{AllocationStrategyFirstFit, AllocationStrategyBestFit, AllocationStrategyLastFit}
+)
+
+// ParseAllocationStrategy returns the allocation strategy for value, or an error if it isn't one of the supported strategies; an empty value returns AllocationStrategyDefault
+func ParseAllocationStrategy(value string) (as AllocationStrategy, err error) {
+	if value == "" {
+		return AllocationStrategyDefault, nil
+	}
+
+	for _, as := range allocationStrategies {
+		if string(as) == value {
+			return as, nil
+		}
+	}
+
+	names := []string{}
+	for _, as := range allocationStrategies {
+		names = append(names, string(as))
+	}
+
+	return AllocationStrategyDefault, fmt.Errorf("Value %q for field allocation_strategy is invalid; please set to %v", value, joinWithOr(names))
+}
+
+// UnmarshalJSON rejects values other than the supported strategies, so a typo doesn't silently fall back to first-fit
+func (as *AllocationStrategy) UnmarshalJSON(data []byte) (err error) {
+	var value string
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return
+	}
+
+	*as, err = ParseAllocationStrategy(value)
+	return
+}
+
+// UnmarshalYAML rejects values other than the supported strategies, so a typo doesn't silently fall back to first-fit
+func (as *AllocationStrategy) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
+	var value string
+	err = unmarshal(&value)
+	if err != nil {
+		return
+	}
+
+	*as, err = ParseAllocationStrategy(value)
+	return
+}
@@ -0,0 +1,63 @@
+package network
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAllocationStrategy(t *testing.T) {
+
+	t.Run("ReturnsDefaultForEmptyValue", func(t *testing.T) {
+
+		// act
+		allocationStrategy, err := ParseAllocationStrategy("")
+
+		assert.Nil(t, err)
+		assert.Equal(t, AllocationStrategyDefault, allocationStrategy)
+	})
+
+	t.Run("ReturnsSupportedStrategy", func(t *testing.T) {
+
+		// act
+		allocationStrategy, err := ParseAllocationStrategy("last-fit")
+
+		assert.Nil(t, err)
+		assert.Equal(t, AllocationStrategyLastFit, allocationStrategy)
+	})
+
+	t.Run("ReturnsErrorForUnsupportedStrategy", func(t *testing.T) {
+
+		// act
+		_, err := ParseAllocationStrategy("worst-fit")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Value \"worst-fit\" for field allocation_strategy is invalid; please set to first-fit, best-fit or last-fit", err.Error())
+	})
+}
+
+func TestAllocationStrategyUnmarshalJSON(t *testing.T) {
+
+	t.Run("ReturnsSupportedStrategy", func(t *testing.T) {
+
+		var allocationStrategy AllocationStrategy
+
+		// act
+		err := json.Unmarshal([]byte(`"best-fit"`), &allocationStrategy)
+
+		assert.Nil(t, err)
+		assert.Equal(t, AllocationStrategyBestFit, allocationStrategy)
+	})
+
+	t.Run("ReturnsErrorForUnsupportedStrategy", func(t *testing.T) {
+
+		var allocationStrategy AllocationStrategy
+
+		// act
+		err := json.Unmarshal([]byte(`"worst-fit"`), &allocationStrategy)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Value \"worst-fit\" for field allocation_strategy is invalid; please set to first-fit, best-fit or last-fit", err.Error())
+	})
+}
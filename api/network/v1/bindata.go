@@ -77,7 +77,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _configJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbc\x92\x41\x6b\xfa\x40\x10\xc5\xef\xf9\x14\x8f\x9c\xfe\x7f\xb0\xd1\x04\x9b\xaa\xf7\x1e\x0a\x3d\xf4\x58\x28\x25\xac\xc9\x24\x2e\x6d\x76\xc2\xec\x46\x11\xf1\xbb\x97\x8d\x4a\x2a\x6d\xea\x41\x5a\x02\x39\xcc\x0c\x6f\x7e\x6f\xde\xee\x02\x20\x14\x65\x2a\xca\x72\x36\xa5\xae\x6c\xb8\xc0\x4b\x00\x00\xbb\xee\x0f\x84\x6e\xdb\x50\xb8\x40\x68\xb8\xa0\x70\x74\xaa\x0a\x55\x9a\x8d\xaf\x53\x2b\xdc\xd0\xcd\x86\xac\x8b\xfb\xbe\x6e\xb2\x5c\x17\x92\x1d\xc4\x4f\x1a\x8d\xe8\x5a\xc9\xb6\x1f\xcb\xb9\xae\xc9\x38\xdf\xbb\x5f\x93\x6c\x61\xdb\xa5\x21\x87\x95\xb2\x28\xb9\x15\x08\x59\x92\x35\x15\x78\x78\x82\x2a\x0a\x21\x6b\xc9\x42\x1b\x68\x67\x71\x94\xf3\xbd\x6e\x4f\xaf\x6b\xc8\x6d\x58\xde\xbc\x6e\x7c\x97\x44\xc9\x2c\x9a\x44\x93\x71\x3c\xed\x27\x0e\x8b\xb2\x5a\x59\x3f\x95\xc4\x5d\x7d\x3f\xfa\xea\xbb\xe1\xe2\x5a\xdb\x96\x72\x36\xc5\x99\xf1\xcf\x80\x93\xa8\xfb\xc6\xf3\x6f\xef\xf2\xa8\xa4\x22\x90\xe1\xb6\x5a\xc1\x31\x4a\xed\xf0\xcf\xb1\x53\xef\x30\x6d\xbd\x24\x01\x97\xf0\xe1\x58\x3c\x23\xb9\x4d\xff\x9f\x9d\x6a\xc8\x70\x9c\x0e\x1a\xf6\x07\xd7\xf9\xd5\x59\x5f\x30\xed\x53\x99\x5e\x4a\x25\x19\x84\xac\x95\x75\x24\xbf\xcc\x38\x4f\xa2\x38\x3d\x3e\x9d\xd9\x20\xe4\x6c\x10\x92\xdd\xea\xaf\x18\xd3\xe9\xcf\x90\xc7\x4b\x06\xc0\x6b\xb0\xff\x08\x00\x00\xff\xff\x0c\x9f\x85\xcc\xf7\x03\x00\x00")
+var _configJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xad\x51\x41\x4b\xc3\x30\x18\xbd\xef\x57\x7c\xf4\xa4\x30\xda\xb5\xd4\xda\x79\x15\x0f\x82\x07\x4f\x22\x88\x94\xac\xf9\xda\x85\xd9\xa4\x7c\x49\x27\x63\xec\xbf\x9b\xa4\x95\x3a\x5c\xdd\x61\x12\x08\xe4\xbd\x97\x2f\xef\xbd\xec\x67\x00\x01\x6b\xc5\x0b\x92\x16\x4a\x06\x77\x10\x48\x34\x9f\x8a\x36\xd1\x36\x0e\xe6\x8e\xdd\x08\xc9\x1d\x7e\xaf\x64\x25\xea\x1e\x23\x26\x6b\x2c\x4a\x8f\x68\x4b\xbe\x59\x10\x60\xef\x77\x4b\x9b\x5d\x8b\x7e\x94\xe2\xe8\x2f\x78\x54\xb4\x45\x29\x38\x15\xfd\xe5\x6f\x4d\x4b\xa2\x61\xb4\x1b\x65\xa5\x6a\x1a\x94\xc6\x71\x0f\x5b\xa4\x1d\xe8\x6e\x65\x3d\xc1\x9a\x69\xa8\x54\x47\x40\xa8\x91\xb6\xc8\xe1\xf1\x19\x18\xe7\xf6\xa8\x51\x83\x90\x20\x8c\x86\x61\x9c\xe3\xfc\x3b\xe3\xdc\x21\x97\x9b\x1b\xdf\x26\x61\x92\x87\x8b\x70\x11\xc5\xe9\xa8\xe8\x1f\x2a\x1a\xa6\x9d\x2a\x89\x3d\x7e\x98\xff\xce\xd5\x2a\x7e\x2e\x96\x46\xdb\x0e\x3f\x0a\xf6\xd3\xc0\x22\xf4\x2b\x5a\x9e\xcc\xfd\xc4\xa8\x46\x40\xa9\xba\x7a\x0d\x46\x41\x25\x0c\x5c\x19\x65\xd8\x07\xc8\xae\x59\x21\x81\xaa\xc0\x95\xab\xe1\x15\x92\x9b\xec\xfa\xa8\x8a\xa9\x40\x71\x36\x19\xc8\x15\x2a\x4a\xbc\x30\x94\x6b\x35\x3d\xd7\x6a\x32\x69\xc2\x2a\x0c\xd2\x85\x1e\x96\x49\x18\x67\xc3\xd7\xe6\x93\x26\xf2\x49\x13\xca\xac\xff\xcb\x43\x96\xfe\x6d\x62\x68\xc2\xee\xef\xb3\xc3\x17\x0d\xef\x7e\x8f\x89\x03\x00\x00")
 
 func configJsonBytes() ([]byte, error) {
 	return bindataRead(
@@ -92,7 +92,7 @@ func configJson() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config.json", size: 1015, mode: os.FileMode(420), modTime: time.Unix(1600091660, 0)}
+	info := bindataFileInfo{name: "config.json", size: 905, mode: os.FileMode(420), modTime: time.Unix(1792312563, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -1,18 +1,185 @@
 package network
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// APIVersion is the schema version of configs this package can parse
+	APIVersion = "network/v1"
+	// KindConfig is the kind of the config document
+	KindConfig = "Config"
+)
+
 type Config struct {
-	RangeConfigs []RangeConfig `json:"range_configs"`
+	APIVersion   string        `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind         string        `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Types        []Type        `json:"types,omitempty" yaml:"types,omitempty"`
+	RangeConfigs []RangeConfig `json:"range_configs" yaml:"range_configs"`
+}
+
+// configHeader is decoded before the rest of the config, so documents with another schema version aren't misparsed
+type configHeader struct {
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string `json:"kind" yaml:"kind"`
+}
+
+// ParseConfig decodes a json or yaml config; the format is taken from the extension of path or, if that's not .json, .yaml or .yml, from the content
+func ParseConfig(data []byte, path string) (config *Config, err error) {
+
+	unmarshal := json.Unmarshal
+	unmarshalStrict := unmarshalJSONStrict
+	if isYAML(data, path) {
+		unmarshal = yaml.Unmarshal
+		unmarshalStrict = yaml.UnmarshalStrict
+	}
+
+	var header configHeader
+	err = unmarshal(data, &header)
+	if err != nil {
+		return
+	}
+
+	// configs without header predate versioning and are parsed as the current version
+	if header.APIVersion != "" && header.APIVersion != APIVersion {
+		return nil, fmt.Errorf("Value for field apiVersion is %v, but only %v is supported; please migrate the config to %v", header.APIVersion, APIVersion, APIVersion)
+	}
+	if header.Kind != "" && header.Kind != KindConfig {
+		return nil, fmt.Errorf("Value for field kind is %v; please set to %v", header.Kind, KindConfig)
+	}
+
+	// reject unknown fields, so a typo in a field name doesn't go unnoticed
+	err = unmarshalStrict(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("Config is invalid: %v", err)
+	}
+
+	return
+}
+
+func unmarshalJSONStrict(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(v)
+}
+
+func isYAML(data []byte, path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return false
+	case ".yaml", ".yml":
+		return true
+	}
+
+	return !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
 }
 
 func (c *Config) Validate() (valid bool, warnings []string, errors []string) {
 
-	// validate all range configs
-	for _, rc := range c.RangeConfigs {
-		_, w, e := rc.Validate()
+	// validate header
+	if c.APIVersion == "" || c.Kind == "" {
+		warnings = append(warnings, fmt.Sprintf("Fields apiVersion and kind are missing; please set them to %v and %v, so the config can be migrated when the schema changes", APIVersion, KindConfig))
+	}
+
+	// validate all range configs, prefixing messages with the path to the invalid field
+	for i, rc := range c.RangeConfigs {
+		w, e := rc.validateFields()
 
-		warnings = append(warnings, w...)
-		errors = append(errors, e...)
+		for _, m := range w {
+			warnings = append(warnings, fmt.Sprintf("%v: %v", c.getFieldPath(i, m.field), m.message))
+		}
+		for _, m := range e {
+			errors = append(errors, fmt.Sprintf("%v: %v", c.getFieldPath(i, m.field), m.message))
+		}
+	}
+
+	// validate types are declared once and range configs only use declared types
+	declaredTypes := map[Type]bool{}
+	for i, t := range c.Types {
+		if declaredTypes[t] {
+			errors = append(errors, fmt.Sprintf("types[%v]: Value %v is declared more than once", i, t))
+		}
+		declaredTypes[t] = true
+	}
+	for i, rc := range c.RangeConfigs {
+		if rc.Type != TypeUnknown && !c.IsDeclaredType(rc.Type) {
+			errors = append(errors, fmt.Sprintf("%v: Value %v for field type is not declared; please set to %v or add it to types", c.getFieldPath(i, "type"), rc.Type, c.describeTypes()))
+		}
+	}
+
+	// validate range configs against each other
+	for i, a := range c.RangeConfigs {
+		for j := i + 1; j < len(c.RangeConfigs); j++ {
+			b := c.RangeConfigs[j]
+
+			if a.Type == b.Type && a.Selector == b.Selector {
+				errors = append(errors, fmt.Sprintf("%v: Combination of type and region, environment and network_name is the same as for %v; only one range can be configured per combination", c.getFieldPath(j, ""), c.getFieldPath(i, "")))
+			}
+
+			if networksOverlap(a.NetworkCIDR, b.NetworkCIDR) {
+				errors = append(errors, fmt.Sprintf("%v: Value for field network %v overlaps with %v %v", c.getFieldPath(j, "network"), b.NetworkCIDR, c.getFieldPath(i, "network"), a.NetworkCIDR))
+			}
+		}
 	}
 
 	return len(errors) == 0, warnings, errors
 }
+
+// GetTypes returns the types declared in the config, or the built-in types if it doesn't declare any
+func (c *Config) GetTypes() []Type {
+	if len(c.Types) > 0 {
+		return c.Types
+	}
+
+	return BuiltInTypes
+}
+
+// IsDeclaredType returns true if the config declares the type, or if it's a built-in type and the config doesn't declare any
+func (c *Config) IsDeclaredType(networkType Type) bool {
+	for _, t := range c.GetTypes() {
+		if t == networkType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Config) describeTypes() string {
+	names := []string{}
+	for _, t := range c.GetTypes() {
+		names = append(names, string(t))
+	}
+
+	return joinWithOr(names)
+}
+
+// getFieldPath returns the json path to a field of a range config, like range_configs[2].subnet_mask
+func (c *Config) getFieldPath(index int, field string) string {
+	if field == "" {
+		return fmt.Sprintf("range_configs[%v]", index)
+	}
+	return fmt.Sprintf("range_configs[%v].%v", index, field)
+}
+
+// networksOverlap returns true if both networks are valid and overlap; aligned networks overlap if one of them contains the base address of the other
+func networksOverlap(networkA, networkB string) bool {
+	_, ipnetA, err := net.ParseCIDR(networkA)
+	if err != nil {
+		return false
+	}
+	_, ipnetB, err := net.ParseCIDR(networkB)
+	if err != nil {
+		return false
+	}
+
+	return ipnetA.Contains(ipnetB.IP) || ipnetB.Contains(ipnetA.IP)
+}
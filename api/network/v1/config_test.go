@@ -1,6 +1,7 @@
 package network
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,10 +19,315 @@ func TestConfigValidate(t *testing.T) {
 		assert.True(t, valid)
 		assert.Equal(t, 0, len(errors))
 	})
+
+	t.Run("ReturnsWarningWhenAPIVersionAndKindAreMissing", func(t *testing.T) {
+
+		config := getValidConfig()
+		config.APIVersion = ""
+		config.Kind = ""
+
+		// act
+		valid, warnings, _ := config.Validate()
+
+		assert.True(t, valid)
+		assert.Equal(t, 1, len(warnings))
+		assert.True(t, strings.HasPrefix(warnings[0], "Fields apiVersion and kind are missing"))
+	})
+
+	t.Run("ReturnsErrorWhenTypeIsNotBuiltInAndConfigDoesNotDeclareTypes", func(t *testing.T) {
+
+		config := getValidConfig()
+		config.RangeConfigs[1].Type = "nod"
+
+		// act
+		valid, _, errors := config.Validate()
+
+		assert.False(t, valid)
+		assert.Equal(t, 1, len(errors))
+		assert.Equal(t, "range_configs[1].type: Value nod for field type is not declared; please set to node, pod, service, master or other or add it to types", errors[0])
+	})
+
+	t.Run("ReturnsNoErrorsWhenTypesAreDeclared", func(t *testing.T) {
+
+		config := getValidConfig()
+		config.Types = []Type{TypeNode, TypePod, "psa"}
+		config.RangeConfigs = append(config.RangeConfigs, RangeConfig{
+			Type:        "psa",
+			RangeType:   RangeTypePrimary,
+			NetworkCIDR: "192.168.0.0/16",
+			SubnetMask:  20,
+		})
+
+		// act
+		valid, _, errors := config.Validate()
+
+		assert.True(t, valid)
+		assert.Equal(t, 0, len(errors))
+	})
+
+	t.Run("ReturnsErrorWhenBuiltInTypeIsNotDeclared", func(t *testing.T) {
+
+		config := getValidConfig()
+		config.Types = []Type{TypeNode, "psa"}
+
+		// act
+		valid, _, errors := config.Validate()
+
+		assert.False(t, valid)
+		assert.Equal(t, 1, len(errors))
+		assert.Equal(t, "range_configs[1].type: Value pod for field type is not declared; please set to node or psa or add it to types", errors[0])
+	})
+
+	t.Run("ReturnsErrorWhenTypeIsDeclaredTwice", func(t *testing.T) {
+
+		config := getValidConfig()
+		config.Types = []Type{TypeNode, TypePod, TypeNode}
+
+		// act
+		valid, _, errors := config.Validate()
+
+		assert.False(t, valid)
+		assert.Equal(t, 1, len(errors))
+		assert.Equal(t, "types[2]: Value node is declared more than once", errors[0])
+	})
+
+	t.Run("ReturnsErrorNamingRangeConfigWhenRangeConfigIsInvalid", func(t *testing.T) {
+
+		config := getValidConfig()
+		config.RangeConfigs[1].SubnetMask = 33
+
+		// act
+		valid, _, errors := config.Validate()
+
+		assert.False(t, valid)
+		assert.Equal(t, 1, len(errors))
+		assert.Equal(t, "range_configs[1].subnet_mask: Value for field subnet_mask is invalid; it needs to be between 9 and 32", errors[0])
+	})
+
+	t.Run("ReturnsErrorWhenNetworksOfRangeConfigsOverlap", func(t *testing.T) {
+
+		config := getValidConfig()
+		config.RangeConfigs = append(config.RangeConfigs, RangeConfig{
+			Type:        TypeService,
+			RangeType:   RangeTypeSecondary,
+			NetworkCIDR: "172.30.0.0/16",
+			SubnetMask:  22,
+		})
+
+		// act
+		valid, _, errors := config.Validate()
+
+		assert.False(t, valid)
+		assert.Equal(t, 1, len(errors))
+		assert.Equal(t, "range_configs[2].network: Value for field network 172.30.0.0/16 overlaps with range_configs[0].network 172.28.0.0/14", errors[0])
+	})
+
+	t.Run("ReturnsErrorWhenTypeAndSelectorAreDuplicated", func(t *testing.T) {
+
+		config := getValidConfig()
+		config.RangeConfigs[0].Region = "europe-west1"
+		config.RangeConfigs = append(config.RangeConfigs, RangeConfig{
+			Type:        TypeNode,
+			RangeType:   RangeTypePrimary,
+			NetworkCIDR: "172.16.0.0/14",
+			SubnetMask:  21,
+			Selector: Selector{
+				Region: "europe-west1",
+			},
+		})
+
+		// act
+		valid, _, errors := config.Validate()
+
+		assert.False(t, valid)
+		assert.Equal(t, 1, len(errors))
+		assert.Equal(t, "range_configs[2]: Combination of type and region, environment and network_name is the same as for range_configs[0]; only one range can be configured per combination", errors[0])
+	})
+
+	t.Run("ReturnsNoErrorsWhenTypeIsRepeatedForDifferentSelectors", func(t *testing.T) {
+
+		config := getValidConfig()
+		config.RangeConfigs = append(config.RangeConfigs, RangeConfig{
+			Type:        TypeNode,
+			RangeType:   RangeTypePrimary,
+			NetworkCIDR: "172.16.0.0/14",
+			SubnetMask:  21,
+			Selector: Selector{
+				Region: "europe-west1",
+			},
+		})
+
+		// act
+		valid, _, errors := config.Validate()
+
+		assert.True(t, valid)
+		assert.Equal(t, 0, len(errors))
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+
+	t.Run("ReturnsConfigFromJSON", func(t *testing.T) {
+
+		data := []byte(`{"apiVersion": "network/v1", "kind": "Config", "range_configs": [{"type": "node", "ip_cidr_range_type": "primary", "network": "172.28.0.0/14", "subnet_mask": 21, "region": "europe-west1"}]}`)
+
+		// act
+		config, err := ParseConfig(data, "config.json")
+
+		assert.Nil(t, err)
+		assert.Equal(t, APIVersion, config.APIVersion)
+		assert.Equal(t, KindConfig, config.Kind)
+		assert.Equal(t, 1, len(config.RangeConfigs))
+		assert.Equal(t, TypeNode, config.RangeConfigs[0].Type)
+		assert.Equal(t, "europe-west1", config.RangeConfigs[0].Region)
+	})
+
+	t.Run("ReturnsConfigFromYAMLBasedOnExtension", func(t *testing.T) {
+
+		data := []byte(`apiVersion: network/v1
+kind: Config
+range_configs:
+- type: pod
+  ip_cidr_range_type: secondary
+  network: 10.0.0.0/9
+  subnet_mask: 16
+  environment: prd
+`)
+
+		// act
+		config, err := ParseConfig(data, "config.yml")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(config.RangeConfigs))
+		assert.Equal(t, TypePod, config.RangeConfigs[0].Type)
+		assert.Equal(t, RangeTypeSecondary, config.RangeConfigs[0].RangeType)
+		assert.Equal(t, "10.0.0.0/9", config.RangeConfigs[0].NetworkCIDR)
+		assert.Equal(t, 16, config.RangeConfigs[0].SubnetMask)
+		assert.Equal(t, "prd", config.RangeConfigs[0].Environment)
+	})
+
+	t.Run("ReturnsConfigFromYAMLBasedOnContentIfExtensionIsUnknown", func(t *testing.T) {
+
+		data := []byte(`range_configs:
+- type: node
+  ip_cidr_range_type: primary
+  network: 172.28.0.0/14
+  subnet_mask: 21
+`)
+
+		// act
+		config, err := ParseConfig(data, "config")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(config.RangeConfigs))
+		assert.Equal(t, "", config.APIVersion)
+	})
+
+	t.Run("ReturnsErrorIfAPIVersionIsNotSupported", func(t *testing.T) {
+
+		data := []byte(`{"apiVersion": "network/v2", "kind": "Config", "range_configs": {"node": []}}`)
+
+		// act
+		_, err := ParseConfig(data, "config.json")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Value for field apiVersion is network/v2, but only network/v1 is supported; please migrate the config to network/v1", err.Error())
+	})
+
+	t.Run("ReturnsErrorIfJSONHasUnknownField", func(t *testing.T) {
+
+		data := []byte(`{"range_configs": [{"type": "node", "ip_cidr_range": "primary", "network": "172.28.0.0/14", "subnet_mask": 21}]}`)
+
+		// act
+		_, err := ParseConfig(data, "config.json")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Config is invalid: json: unknown field \"ip_cidr_range\"", err.Error())
+	})
+
+	t.Run("ReturnsErrorIfYAMLHasUnknownField", func(t *testing.T) {
+
+		data := []byte(`range_configs:
+- type: node
+  ip_cidr_range_type: primary
+  network: 172.28.0.0/14
+  subnetmask: 21
+`)
+
+		// act
+		_, err := ParseConfig(data, "config.yaml")
+
+		assert.NotNil(t, err)
+		assert.True(t, strings.Contains(err.Error(), "field subnetmask not found"))
+	})
+
+	t.Run("ReturnsErrorIfTypeIsInvalidName", func(t *testing.T) {
+
+		data := []byte(`{"range_configs": [{"type": "Node", "ip_cidr_range_type": "primary", "network": "172.28.0.0/14", "subnet_mask": 21}]}`)
+
+		// act
+		_, err := ParseConfig(data, "config.json")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Config is invalid: Value \"Node\" for field type is invalid; please use lowercase letters, digits, hyphens and underscores, starting with a letter", err.Error())
+	})
+
+	t.Run("ReturnsErrorIfRangeTypeIsNotDeclaredInYAML", func(t *testing.T) {
+
+		data := []byte(`range_configs:
+- type: node
+  ip_cidr_range_type: primery
+  network: 172.28.0.0/14
+  subnet_mask: 21
+`)
+
+		// act
+		_, err := ParseConfig(data, "config.yaml")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Config is invalid: Value \"primery\" for field ip_cidr_range_type is invalid; please set to primary or secondary", err.Error())
+	})
+
+	t.Run("ReturnsErrorIfKindIsNotConfig", func(t *testing.T) {
+
+		data := []byte(`{"apiVersion": "network/v1", "kind": "Reservations"}`)
+
+		// act
+		_, err := ParseConfig(data, "config.json")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Value for field kind is Reservations; please set to Config", err.Error())
+	})
+}
+
+func TestGetTypes(t *testing.T) {
+
+	t.Run("ReturnsBuiltInTypesIfConfigDoesNotDeclareTypes", func(t *testing.T) {
+
+		config := getValidConfig()
+
+		// act
+		types := config.GetTypes()
+
+		assert.Equal(t, BuiltInTypes, types)
+	})
+
+	t.Run("ReturnsDeclaredTypes", func(t *testing.T) {
+
+		config := getValidConfig()
+		config.Types = []Type{"psa", "proxy-only"}
+
+		// act
+		types := config.GetTypes()
+
+		assert.Equal(t, []Type{"psa", "proxy-only"}, types)
+	})
 }
 
 func getValidConfig() Config {
 	return Config{
+		APIVersion: APIVersion,
+		Kind:       KindConfig,
 		RangeConfigs: []RangeConfig{
 			{
 				Type:        TypeNode,
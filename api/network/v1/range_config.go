@@ -2,83 +2,130 @@ package network
 
 import (
 	"fmt"
-	"math"
 	"net"
-
-	"github.com/apparentlymart/go-cidr/cidr"
 )
 
 type RangeConfig struct {
-	Type        Type      `json:"type"`
-	RangeType   RangeType `json:"ip_cidr_range_type"`
-	NetworkCIDR string    `json:"network"`
-	Comment     string    `json:"comment"`
-	SubnetMask  int       `json:"subnet_mask"`
+	Type        Type      `json:"type" yaml:"type"`
+	RangeType   RangeType `json:"ip_cidr_range_type" yaml:"ip_cidr_range_type"`
+	NetworkCIDR string    `json:"network" yaml:"network"`
+	Comment     string    `json:"comment,omitempty" yaml:"comment,omitempty"`
+	SubnetMask  int       `json:"subnet_mask" yaml:"subnet_mask"`
+
+	// MinSubnetMask and MaxSubnetMask allow suggesting larger or smaller ranges than subnet_mask on request; when not set only subnet_mask is allowed
+	MinSubnetMask int `json:"min_subnet_mask,omitempty" yaml:"min_subnet_mask,omitempty"`
+	MaxSubnetMask int `json:"max_subnet_mask,omitempty" yaml:"max_subnet_mask,omitempty"`
+
+	// AllocationStrategy determines which free candidate is suggested; when not set first-fit is used
+	AllocationStrategy AllocationStrategy `json:"allocation_strategy,omitempty" yaml:"allocation_strategy,omitempty"`
+
+	Selector `yaml:",inline"`
 }
 
 func (rc *RangeConfig) Validate() (valid bool, warnings []string, errors []string) {
+	w, e := rc.validateFields()
+
+	for _, m := range w {
+		warnings = append(warnings, m.message)
+	}
+	for _, m := range e {
+		errors = append(errors, m.message)
+	}
+
+	return len(errors) == 0, warnings, errors
+}
+
+// fieldMessage is a validation message for a single field, so the config can report it with the path to that field
+type fieldMessage struct {
+	field   string
+	message string
+}
+
+func (rc *RangeConfig) validateFields() (warnings []fieldMessage, errors []fieldMessage) {
 	// validate type
 	if rc.Type == TypeUnknown {
-		errors = append(errors, "Value for field type is unknown; please set to node, pod, service, master or other")
+		errors = append(errors, fieldMessage{"type", "Value for field type is unknown; please set to one of the declared types"})
 	}
 
 	// validate ip_cidr_range_type
 	if rc.RangeType == RangeTypeUnknown {
-		errors = append(errors, "Value for field ip_cidr_range_type is unknown; please set to primary or secondary")
+		errors = append(errors, fieldMessage{"ip_cidr_range_type", "Value for field ip_cidr_range_type is unknown; please set to primary or secondary"})
 	}
 
 	// validate network
-	_, ipnet, err := net.ParseCIDR(rc.NetworkCIDR)
+	ip, ipnet, err := net.ParseCIDR(rc.NetworkCIDR)
 	if err != nil {
-		errors = append(errors, fmt.Sprintf("Value for field network is invalid: %v", err.Error()))
+		errors = append(errors, fieldMessage{"network", fmt.Sprintf("Value for field network is invalid: %v", err.Error())})
 	} else {
 
-		// validate subnet_mask
-		ones, _ := ipnet.Mask.Size()
-		if rc.SubnetMask >= 0 && rc.SubnetMask <= 32 {
+		// validate the base address is aligned to the mask, otherwise the range is silently treated as the network containing it
+		if !ip.Equal(ipnet.IP) {
+			errors = append(errors, fieldMessage{"network", fmt.Sprintf("Value for field network is not aligned to its mask; the base address for %v is %v", rc.NetworkCIDR, ipnet)})
+		}
+
+		// validate subnet_mask, which can go up to 32 for ipv4 and 128 for ipv6 networks
+		ones, bits := ipnet.Mask.Size()
+		if rc.SubnetMask >= 0 && rc.SubnetMask <= bits {
 			if rc.SubnetMask < ones {
-				errors = append(errors, fmt.Sprintf("Value for field subnet_mask is less than the network mask: %v < %v", rc.SubnetMask, ones))
+				errors = append(errors, fieldMessage{"subnet_mask", fmt.Sprintf("Value for field subnet_mask is less than the network mask: %v < %v", rc.SubnetMask, ones)})
 			}
 		} else {
-			errors = append(errors, fmt.Sprintf("Value for field subnet_mask is invalid; it needs to be between %v and 32", ones))
+			errors = append(errors, fieldMessage{"subnet_mask", fmt.Sprintf("Value for field subnet_mask is invalid; it needs to be between %v and %v", ones, bits)})
+		}
+
+		// validate min_subnet_mask and max_subnet_mask surround subnet_mask
+		if rc.MinSubnetMask != 0 && (rc.MinSubnetMask < ones || rc.MinSubnetMask > rc.SubnetMask) {
+			errors = append(errors, fieldMessage{"min_subnet_mask", fmt.Sprintf("Value for field min_subnet_mask is invalid; it needs to be between %v and the subnet_mask %v", ones, rc.SubnetMask)})
 		}
+		if rc.MaxSubnetMask != 0 && (rc.MaxSubnetMask < rc.SubnetMask || rc.MaxSubnetMask > bits) {
+			errors = append(errors, fieldMessage{"max_subnet_mask", fmt.Sprintf("Value for field max_subnet_mask is invalid; it needs to be between the subnet_mask %v and %v", rc.SubnetMask, bits)})
+		}
+
+		warnings = append(warnings, rc.validateAddressSpace(ipnet)...)
 	}
 
-	return len(errors) == 0, warnings, errors
+	return
 }
 
-func (rc *RangeConfig) GetMaxSubnetworkRanges() int {
-	_, networkIPnet, err := net.ParseCIDR(rc.NetworkCIDR)
-	if err != nil {
-		return -1
+// GetSubnetMaskBounds returns the smallest and largest prefix length that can be suggested
+func (rc *RangeConfig) GetSubnetMaskBounds() (minSubnetMask, maxSubnetMask int) {
+	minSubnetMask, maxSubnetMask = rc.SubnetMask, rc.SubnetMask
+	if rc.MinSubnetMask != 0 {
+		minSubnetMask = rc.MinSubnetMask
+	}
+	if rc.MaxSubnetMask != 0 {
+		maxSubnetMask = rc.MaxSubnetMask
 	}
 
-	ones, _ := networkIPnet.Mask.Size()
-	onesDiff := rc.SubnetMask - ones
-
-	return int(math.Pow(2, float64(onesDiff)))
+	return
 }
 
-func (rc *RangeConfig) GetAvailableSubnetworkRanges() (subnetRanges []*net.IPNet) {
-
-	_, ipnet, err := net.ParseCIDR(rc.NetworkCIDR)
+// GetSubnetMask returns the prefix length to suggest for the requested size, or subnet_mask if the size doesn't apply to the type
+func (rc *RangeConfig) GetSubnetMask(size SubnetSize) (subnetMask int, err error) {
+	prefixLength, ok, err := size.GetPrefixLength(rc.Type)
 	if err != nil {
 		return
 	}
+	if !ok {
+		return rc.SubnetMask, nil
+	}
 
-	ones, _ := ipnet.Mask.Size()
-
-	i := 0
-	maxRanges := rc.GetMaxSubnetworkRanges()
-	for i < maxRanges {
+	minSubnetMask, maxSubnetMask := rc.GetSubnetMaskBounds()
+	if prefixLength < minSubnetMask || prefixLength > maxSubnetMask {
+		return 0, fmt.Errorf("Requested size /%v for type %v is outside of the sizes /%v to /%v allowed for range %v", prefixLength, rc.Type, minSubnetMask, maxSubnetMask, rc.NetworkCIDR)
+	}
 
-		subnetRange, err := cidr.Subnet(ipnet, rc.SubnetMask-ones, i)
-		if err == nil {
-			subnetRanges = append(subnetRanges, subnetRange)
-		}
+	return prefixLength, nil
+}
 
-		i++
+// GetAllocationStrategy returns the override if set, otherwise the allocation_strategy of the range config, defaulting to first-fit
+func (rc *RangeConfig) GetAllocationStrategy(override AllocationStrategy) AllocationStrategy {
+	if override != AllocationStrategyDefault {
+		return override
+	}
+	if rc.AllocationStrategy != AllocationStrategyDefault {
+		return rc.AllocationStrategy
 	}
 
-	return
+	return AllocationStrategyFirstFit
 }
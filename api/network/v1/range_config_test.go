@@ -59,6 +59,45 @@ func TestRangeConfigValidate(t *testing.T) {
 		assert.Equal(t, "Value for field network is invalid: invalid CIDR address: 192.0.2.0/388", errors[0])
 	})
 
+	t.Run("ReturnsErrorWhenNetworkIsNotAlignedToItsMask", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.NetworkCIDR = "172.29.0.0/14"
+
+		// act
+		valid, _, errors := rangeConfig.Validate()
+
+		assert.False(t, valid)
+		assert.Equal(t, 1, len(errors))
+		assert.Equal(t, "Value for field network is not aligned to its mask; the base address for 172.29.0.0/14 is 172.28.0.0/14", errors[0])
+	})
+
+	t.Run("ReturnsErrorWhenMinSubnetMaskIsLargerThanSubnetMask", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.MinSubnetMask = 22
+
+		// act
+		valid, _, errors := rangeConfig.Validate()
+
+		assert.False(t, valid)
+		assert.Equal(t, 1, len(errors))
+		assert.Equal(t, "Value for field min_subnet_mask is invalid; it needs to be between 14 and the subnet_mask 21", errors[0])
+	})
+
+	t.Run("ReturnsErrorWhenMaxSubnetMaskIsSmallerThanSubnetMask", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.MaxSubnetMask = 20
+
+		// act
+		valid, _, errors := rangeConfig.Validate()
+
+		assert.False(t, valid)
+		assert.Equal(t, 1, len(errors))
+		assert.Equal(t, "Value for field max_subnet_mask is invalid; it needs to be between the subnet_mask 21 and 32", errors[0])
+	})
+
 	t.Run("ReturnsErrorWhenSubnetMaskIsLessThanNetworkIsInvalidCIDR", func(t *testing.T) {
 
 		rangeConfig := getValidRangeConfig()
@@ -100,49 +139,174 @@ func TestRangeConfigValidate(t *testing.T) {
 		assert.Equal(t, 1, len(errors))
 		assert.Equal(t, "Value for field subnet_mask is invalid; it needs to be between 14 and 32", errors[0])
 	})
+
+	t.Run("ReturnsNoErrorsWhenIPv6RangeConfigIsValid", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.NetworkCIDR = "fd12:3456:789a::/48"
+		rangeConfig.SubnetMask = 64
+
+		// act
+		valid, _, errors := rangeConfig.Validate()
+
+		assert.True(t, valid)
+		assert.Equal(t, 0, len(errors))
+	})
+
+	t.Run("ReturnsErrorWhenSubnetMaskMoreThan128ForIPv6", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.NetworkCIDR = "fd12:3456:789a::/48"
+		rangeConfig.SubnetMask = 129
+
+		// act
+		valid, _, errors := rangeConfig.Validate()
+
+		assert.False(t, valid)
+		assert.Equal(t, 1, len(errors))
+		assert.Equal(t, "Value for field subnet_mask is invalid; it needs to be between 48 and 128", errors[0])
+	})
 }
 
-func TestGetMaxSubnetworkRanges(t *testing.T) {
+func TestRangeConfigValidateWarnings(t *testing.T) {
 
-	t.Run("Returns2IfMaskHasDifferenceOf1", func(t *testing.T) {
+	t.Run("ReturnsNoWarningsWhenRangeConfigIsValid", func(t *testing.T) {
 
 		rangeConfig := getValidRangeConfig()
-		rangeConfig.NetworkCIDR = "172.28.0.0/14"
-		rangeConfig.SubnetMask = 15
 
 		// act
-		maxSubnetworkRanges := rangeConfig.GetMaxSubnetworkRanges()
+		_, warnings, _ := rangeConfig.Validate()
 
-		assert.Equal(t, 2, maxSubnetworkRanges)
+		assert.Equal(t, 0, len(warnings))
 	})
 
-	t.Run("Returns4IfMaskHasDifferenceOf2", func(t *testing.T) {
+	t.Run("ReturnsWarningWhenNetworkOverlapsWithReservedRange", func(t *testing.T) {
 
 		rangeConfig := getValidRangeConfig()
-		rangeConfig.NetworkCIDR = "172.28.0.0/14"
-		rangeConfig.SubnetMask = 16
+		rangeConfig.NetworkCIDR = "169.254.0.0/16"
+
+		// act
+		valid, warnings, _ := rangeConfig.Validate()
+
+		assert.True(t, valid)
+		assert.Equal(t, 2, len(warnings))
+		assert.Equal(t, "Value for field network overlaps with 169.254.0.0/16, which is reserved for link-local addresses, used for the metadata server", warnings[0])
+		assert.True(t, strings.HasPrefix(warnings[1], "Value for field network is not in rfc 1918 address space and will be used as privately used public address space"))
+	})
+
+	t.Run("ReturnsWarningWhenNetworkOverlapsWithGKEDockerBridgeRange", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.NetworkCIDR = "172.16.0.0/14"
+
+		// act
+		_, warnings, _ := rangeConfig.Validate()
+
+		assert.Equal(t, 1, len(warnings))
+		assert.Equal(t, "Value for field network overlaps with 172.17.0.0/16, which is reserved for the docker bridge network on gke nodes", warnings[0])
+	})
+
+	t.Run("ReturnsWarningWhenNetworkIsNonPublicButNotRFC1918", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.NetworkCIDR = "100.64.0.0/14"
+
+		// act
+		_, warnings, _ := rangeConfig.Validate()
+
+		assert.Equal(t, 1, len(warnings))
+		assert.True(t, strings.HasPrefix(warnings[0], "Value for field network is not in rfc 1918 address space but in 100.64.0.0/10 for shared address space (rfc 6598)"))
+	})
+
+	t.Run("ReturnsWarningWhenNetworkIsPrivatelyUsedPublicAddressSpace", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.NetworkCIDR = "11.0.0.0/14"
+
+		// act
+		_, warnings, _ := rangeConfig.Validate()
+
+		assert.Equal(t, 1, len(warnings))
+		assert.True(t, strings.HasPrefix(warnings[0], "Value for field network is not in rfc 1918 address space and will be used as privately used public address space"))
+	})
+
+	t.Run("ReturnsWarningWhenSubnetMaskIsSmallerThanGKEMinimumForType", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.Type = TypePod
+		rangeConfig.RangeType = RangeTypeSecondary
+		rangeConfig.SubnetMask = 26
 
 		// act
-		maxSubnetworkRanges := rangeConfig.GetMaxSubnetworkRanges()
+		_, warnings, _ := rangeConfig.Validate()
 
-		assert.Equal(t, 4, maxSubnetworkRanges)
+		assert.Equal(t, 1, len(warnings))
+		assert.Equal(t, "Value for field subnet_mask is 26, which is smaller than the minimum gke supports for type pod; please set to 24 or less", warnings[0])
+	})
+
+	t.Run("ReturnsWarningWhenSubnetMaskForMasterIsNot28", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.Type = TypeMaster
+		rangeConfig.RangeType = RangeTypeSecondary
+		rangeConfig.SubnetMask = 27
+
+		// act
+		_, warnings, _ := rangeConfig.Validate()
+
+		assert.Equal(t, 1, len(warnings))
+		assert.Equal(t, "Value for field subnet_mask is 27, but gke requires master ranges to be exactly a /28", warnings[0])
+	})
+
+	t.Run("ReturnsNoWarningsForIPv6Network", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.NetworkCIDR = "fd12:3456:789a::/48"
+		rangeConfig.SubnetMask = 64
+
+		// act
+		_, warnings, _ := rangeConfig.Validate()
+
+		assert.Equal(t, 0, len(warnings))
 	})
 }
 
-func TestGetAvailableSubnetworkRanges(t *testing.T) {
+func TestGetSubnetMask(t *testing.T) {
 
-	t.Run("Returns2RangesIfMaskHasDifferenceOf1", func(t *testing.T) {
+	t.Run("ReturnsSubnetMaskIfSizeIsEmpty", func(t *testing.T) {
 
 		rangeConfig := getValidRangeConfig()
-		rangeConfig.NetworkCIDR = "172.28.0.0/14"
-		rangeConfig.SubnetMask = 15
 
 		// act
-		availableSubnetworkRanges := rangeConfig.GetAvailableSubnetworkRanges()
+		subnetMask, err := rangeConfig.GetSubnetMask(SubnetSize{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 21, subnetMask)
+	})
+
+	t.Run("ReturnsRequestedPrefixLengthWithinBounds", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.MinSubnetMask = 18
+		rangeConfig.MaxSubnetMask = 24
+
+		// act
+		subnetMask, err := rangeConfig.GetSubnetMask(SubnetSize{Nodes: 500})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 23, subnetMask)
+	})
+
+	t.Run("ReturnsErrorIfRequestedPrefixLengthIsOutOfBounds", func(t *testing.T) {
+
+		rangeConfig := getValidRangeConfig()
+		rangeConfig.MaxSubnetMask = 24
+
+		// act
+		_, err := rangeConfig.GetSubnetMask(SubnetSize{PrefixLength: 20})
 
-		assert.Equal(t, 2, len(availableSubnetworkRanges))
-		assert.Equal(t, "172.28.0.0/15", availableSubnetworkRanges[0].String())
-		assert.Equal(t, "172.30.0.0/15", availableSubnetworkRanges[1].String())
+		assert.NotNil(t, err)
+		assert.Equal(t, "Requested size /20 for type node is outside of the sizes /21 to /24 allowed for range 172.28.0.0/14", err.Error())
 	})
 }
 
@@ -1,5 +1,10 @@
 package network
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 type RangeType string
 
 const (
@@ -8,3 +13,47 @@ const (
 
 	RangeTypeUnknown RangeType = ""
 )
+
+var (
+	rangeTypes = []RangeType{RangeTypePrimary, RangeTypeSecondary}
+)
+
+// ParseRangeType returns the range type for value, or an error if it isn't one of the declared range types
+func ParseRangeType(value string) (rt RangeType, err error) {
+	for _, rt := range rangeTypes {
+		if string(rt) == value {
+			return rt, nil
+		}
+	}
+
+	names := []string{}
+	for _, rt := range rangeTypes {
+		names = append(names, string(rt))
+	}
+
+	return RangeTypeUnknown, fmt.Errorf("Value %q for field ip_cidr_range_type is invalid; please set to %v", value, joinWithOr(names))
+}
+
+// UnmarshalJSON rejects values other than the declared range types, so a typo isn't silently decoded as an unknown range type
+func (rt *RangeType) UnmarshalJSON(data []byte) (err error) {
+	var value string
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return
+	}
+
+	*rt, err = ParseRangeType(value)
+	return
+}
+
+// UnmarshalYAML rejects values other than the declared range types, so a typo isn't silently decoded as an unknown range type
+func (rt *RangeType) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
+	var value string
+	err = unmarshal(&value)
+	if err != nil {
+		return
+	}
+
+	*rt, err = ParseRangeType(value)
+	return
+}
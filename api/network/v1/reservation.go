@@ -0,0 +1,17 @@
+package network
+
+import "time"
+
+type Reservation struct {
+	CIDR       string     `json:"cidr" yaml:"cidr"`
+	Type       Type       `json:"type,omitempty" yaml:"type,omitempty"`
+	Owner      string     `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Comment    string     `json:"comment,omitempty" yaml:"comment,omitempty"`
+	ReservedAt time.Time  `json:"reserved_at" yaml:"reserved_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+}
+
+// IsActive returns false once the reservation has expired; reservations without expiry stay active until released
+func (r *Reservation) IsActive(now time.Time) bool {
+	return r.ExpiresAt == nil || now.Before(*r.ExpiresAt)
+}
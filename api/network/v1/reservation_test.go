@@ -0,0 +1,43 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReservationIsActive(t *testing.T) {
+
+	t.Run("ReturnsTrueWhenExpiresAtIsNotSet", func(t *testing.T) {
+
+		reservation := Reservation{CIDR: "10.0.0.0/16"}
+
+		// act
+		active := reservation.IsActive(time.Now())
+
+		assert.True(t, active)
+	})
+
+	t.Run("ReturnsTrueWhenExpiresAtIsInTheFuture", func(t *testing.T) {
+
+		expiresAt := time.Now().Add(time.Hour)
+		reservation := Reservation{CIDR: "10.0.0.0/16", ExpiresAt: &expiresAt}
+
+		// act
+		active := reservation.IsActive(time.Now())
+
+		assert.True(t, active)
+	})
+
+	t.Run("ReturnsFalseWhenExpiresAtIsInThePast", func(t *testing.T) {
+
+		expiresAt := time.Now().Add(-time.Hour)
+		reservation := Reservation{CIDR: "10.0.0.0/16", ExpiresAt: &expiresAt}
+
+		// act
+		active := reservation.IsActive(time.Now())
+
+		assert.False(t, active)
+	})
+}
@@ -0,0 +1,25 @@
+package network
+
+// Selector scopes a range config to a region, environment and/or network; empty fields match anything
+type Selector struct {
+	Region      string `json:"region,omitempty" yaml:"region,omitempty"`
+	Environment string `json:"environment,omitempty" yaml:"environment,omitempty"`
+	NetworkName string `json:"network_name,omitempty" yaml:"network_name,omitempty"`
+}
+
+// Matches returns true if all fields set in the selector are equal to the corresponding fields in the target selector
+func (s Selector) Matches(target Selector) bool {
+	return (s.Region == "" || s.Region == target.Region) &&
+		(s.Environment == "" || s.Environment == target.Environment) &&
+		(s.NetworkName == "" || s.NetworkName == target.NetworkName)
+}
+
+// Specificity returns the number of fields set in the selector, used to prefer the most specific range config
+func (s Selector) Specificity() (specificity int) {
+	for _, v := range []string{s.Region, s.Environment, s.NetworkName} {
+		if v != "" {
+			specificity++
+		}
+	}
+	return
+}
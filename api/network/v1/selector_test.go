@@ -0,0 +1,63 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectorMatches(t *testing.T) {
+
+	t.Run("ReturnsTrueWhenSelectorIsEmpty", func(t *testing.T) {
+
+		selector := Selector{}
+
+		// act
+		matches := selector.Matches(Selector{Region: "europe-west1", Environment: "dev", NetworkName: "default"})
+
+		assert.True(t, matches)
+	})
+
+	t.Run("ReturnsTrueWhenAllSetFieldsAreEqual", func(t *testing.T) {
+
+		selector := Selector{Region: "europe-west1", Environment: "dev"}
+
+		// act
+		matches := selector.Matches(Selector{Region: "europe-west1", Environment: "dev", NetworkName: "default"})
+
+		assert.True(t, matches)
+	})
+
+	t.Run("ReturnsFalseWhenRegionIsDifferent", func(t *testing.T) {
+
+		selector := Selector{Region: "europe-west1"}
+
+		// act
+		matches := selector.Matches(Selector{Region: "europe-west4"})
+
+		assert.False(t, matches)
+	})
+
+	t.Run("ReturnsFalseWhenTargetDoesNotSetField", func(t *testing.T) {
+
+		selector := Selector{Environment: "prd"}
+
+		// act
+		matches := selector.Matches(Selector{})
+
+		assert.False(t, matches)
+	})
+}
+
+func TestSelectorSpecificity(t *testing.T) {
+
+	t.Run("ReturnsNumberOfSetFields", func(t *testing.T) {
+
+		selector := Selector{Region: "europe-west1", NetworkName: "default"}
+
+		// act
+		specificity := selector.Specificity()
+
+		assert.Equal(t, 2, specificity)
+	})
+}
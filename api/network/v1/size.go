@@ -0,0 +1,178 @@
+package network
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+const (
+	// reservedAddressesPerSubnet are the network, gateway, second-to-last and broadcast addresses gcp reserves in every primary range
+	reservedAddressesPerSubnet = 4
+
+	// DefaultMaxPodsPerNode is the default maximum number of pods per node in gke standard clusters
+	DefaultMaxPodsPerNode = 110
+
+	minMaxPodsPerNode = 8
+	maxMaxPodsPerNode = 256
+)
+
+// SubnetSize is a requested size for suggested ranges, either a prefix length for every type or the number of nodes, pods per node and services to fit; when empty the subnet_mask of the range config is used
+type SubnetSize struct {
+	PrefixLength   int `json:"prefix_length,omitempty" yaml:"prefix_length,omitempty"`
+	Nodes          int `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	MaxPodsPerNode int `json:"max_pods_per_node,omitempty" yaml:"max_pods_per_node,omitempty"`
+	Services       int `json:"services,omitempty" yaml:"services,omitempty"`
+}
+
+// ParseSubnetSize parses a prefix length like 20 or /20, or counts like nodes=500,max-pods-per-node=110,services=1000
+func ParseSubnetSize(value string) (size SubnetSize, err error) {
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+
+	if !strings.Contains(value, "=") {
+		size.PrefixLength, err = strconv.Atoi(strings.TrimPrefix(value, "/"))
+		if err != nil || size.PrefixLength <= 0 {
+			return size, fmt.Errorf("Size %v is invalid; please set to a prefix length like /20 or to counts like nodes=500,max-pods-per-node=110,services=1000", value)
+		}
+		return
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		keyValue := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(keyValue) != 2 {
+			return size, fmt.Errorf("Size %v is invalid; please set to counts like nodes=500,max-pods-per-node=110,services=1000", value)
+		}
+
+		count, err := strconv.Atoi(strings.TrimSpace(keyValue[1]))
+		if err != nil || count <= 0 {
+			return size, fmt.Errorf("Value %v for %v in size %v is invalid; it needs to be a positive number", keyValue[1], keyValue[0], value)
+		}
+
+		switch strings.TrimSpace(keyValue[0]) {
+		case "nodes":
+			size.Nodes = count
+		case "max-pods-per-node":
+			size.MaxPodsPerNode = count
+		case "services":
+			size.Services = count
+		default:
+			return size, fmt.Errorf("Key %v in size %v is unknown; please use nodes, max-pods-per-node or services", keyValue[0], value)
+		}
+	}
+
+	// the pod range is derived from the number of nodes, so max-pods-per-node on its own would silently be ignored
+	if size.MaxPodsPerNode > 0 && size.Nodes == 0 {
+		return size, fmt.Errorf("Size %v is invalid; max-pods-per-node can only be set together with nodes", value)
+	}
+
+	return
+}
+
+// IsEmpty returns true if no size has been requested
+func (s SubnetSize) IsEmpty() bool {
+	return s == SubnetSize{}
+}
+
+// GetPrefixLength returns the prefix length to suggest for the network type; ok is false if the size doesn't apply to the type, so the default subnet_mask should be used
+func (s SubnetSize) GetPrefixLength(networkType Type) (prefixLength int, ok bool, err error) {
+
+	if s.PrefixLength > 0 {
+		return s.PrefixLength, true, nil
+	}
+
+	switch networkType {
+	case TypeNode:
+		if s.Nodes > 0 {
+			return GetNodePrefixLength(s.Nodes), true, nil
+		}
+
+	case TypePod:
+		if s.Nodes > 0 {
+			maxPodsPerNode := s.MaxPodsPerNode
+			if maxPodsPerNode == 0 {
+				maxPodsPerNode = DefaultMaxPodsPerNode
+			}
+			prefixLength, err = GetPodPrefixLength(s.Nodes, maxPodsPerNode)
+			return prefixLength, err == nil, err
+		}
+
+	case TypeService:
+		if s.Services > 0 {
+			return GetServicePrefixLength(s.Services), true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// ClusterSize holds the prefix lengths of the node, pod and service ranges a gke cluster needs
+type ClusterSize struct {
+	NodePrefixLength    int `json:"node_prefix_length" yaml:"node_prefix_length"`
+	PodPrefixLength     int `json:"pod_prefix_length" yaml:"pod_prefix_length"`
+	ServicePrefixLength int `json:"service_prefix_length" yaml:"service_prefix_length"`
+}
+
+// GetClusterSize returns the prefix lengths of the node, pod and service ranges for a gke cluster with the maximum number of nodes, pods per node and services; when maxPodsPerNode is 0 the gke default of 110 is used
+func GetClusterSize(nodes, maxPodsPerNode, services int) (clusterSize ClusterSize, err error) {
+	if nodes <= 0 {
+		return clusterSize, fmt.Errorf("Value %v for nodes is invalid; it needs to be a positive number", nodes)
+	}
+	if services <= 0 {
+		return clusterSize, fmt.Errorf("Value %v for services is invalid; it needs to be a positive number", services)
+	}
+	if maxPodsPerNode == 0 {
+		maxPodsPerNode = DefaultMaxPodsPerNode
+	}
+
+	podPrefixLength, err := GetPodPrefixLength(nodes, maxPodsPerNode)
+	if err != nil {
+		return
+	}
+
+	return ClusterSize{
+		NodePrefixLength:    GetNodePrefixLength(nodes),
+		PodPrefixLength:     podPrefixLength,
+		ServicePrefixLength: GetServicePrefixLength(services),
+	}, nil
+}
+
+// GetNodePrefixLength returns the prefix length of the smallest primary range fitting the number of nodes, taking the 4 addresses gcp reserves in every subnet into account
+func GetNodePrefixLength(nodes int) int {
+	return minInt(32-ceilLog2(nodes+reservedAddressesPerSubnet), gkeMaxSubnetMasks[TypeNode])
+}
+
+// GetPodPrefixLength returns the prefix length of the smallest pod range fitting the number of nodes; gke gives every node a range with at least twice as many addresses as its maximum number of pods
+func GetPodPrefixLength(nodes, maxPodsPerNode int) (prefixLength int, err error) {
+	if maxPodsPerNode < minMaxPodsPerNode || maxPodsPerNode > maxMaxPodsPerNode {
+		return 0, fmt.Errorf("Value %v for max pods per node is invalid; it needs to be between %v and %v", maxPodsPerNode, minMaxPodsPerNode, maxMaxPodsPerNode)
+	}
+
+	podBitsPerNode := ceilLog2(2 * maxPodsPerNode)
+
+	return minInt(32-podBitsPerNode-ceilLog2(nodes), gkeMaxSubnetMasks[TypePod]), nil
+}
+
+// GetServicePrefixLength returns the prefix length of the smallest service range fitting the number of services
+func GetServicePrefixLength(services int) int {
+	return minInt(32-ceilLog2(services), gkeMaxSubnetMasks[TypeService])
+}
+
+// ceilLog2 returns the number of bits needed to address n values
+func ceilLog2(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
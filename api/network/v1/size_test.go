@@ -0,0 +1,206 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSubnetSize(t *testing.T) {
+
+	t.Run("ReturnsEmptySizeForEmptyValue", func(t *testing.T) {
+
+		// act
+		size, err := ParseSubnetSize("")
+
+		assert.Nil(t, err)
+		assert.True(t, size.IsEmpty())
+	})
+
+	t.Run("ReturnsPrefixLengthWithOrWithoutSlash", func(t *testing.T) {
+
+		// act
+		size, err := ParseSubnetSize("/20")
+		size2, err2 := ParseSubnetSize("20")
+
+		assert.Nil(t, err)
+		assert.Nil(t, err2)
+		assert.Equal(t, SubnetSize{PrefixLength: 20}, size)
+		assert.Equal(t, SubnetSize{PrefixLength: 20}, size2)
+	})
+
+	t.Run("ReturnsCounts", func(t *testing.T) {
+
+		// act
+		size, err := ParseSubnetSize("nodes=500, max-pods-per-node=110,services=1000")
+
+		assert.Nil(t, err)
+		assert.Equal(t, SubnetSize{Nodes: 500, MaxPodsPerNode: 110, Services: 1000}, size)
+	})
+
+	t.Run("ReturnsErrorForUnknownKey", func(t *testing.T) {
+
+		// act
+		_, err := ParseSubnetSize("nodes=500,pods=110")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Key pods in size nodes=500,pods=110 is unknown; please use nodes, max-pods-per-node or services", err.Error())
+	})
+
+	t.Run("ReturnsErrorForMaxPodsPerNodeWithoutNodes", func(t *testing.T) {
+
+		// act
+		_, err := ParseSubnetSize("max-pods-per-node=64,services=1000")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Size max-pods-per-node=64,services=1000 is invalid; max-pods-per-node can only be set together with nodes", err.Error())
+	})
+
+	t.Run("ReturnsErrorForInvalidPrefixLength", func(t *testing.T) {
+
+		// act
+		_, err := ParseSubnetSize("/abc")
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSubnetSizeGetPrefixLength(t *testing.T) {
+
+	t.Run("ReturnsPrefixLengthForEveryType", func(t *testing.T) {
+
+		size := SubnetSize{PrefixLength: 20}
+
+		// act
+		prefixLength, ok, err := size.GetPrefixLength(TypeMaster)
+
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, 20, prefixLength)
+	})
+
+	t.Run("ReturnsPrefixLengthsDerivedFromCounts", func(t *testing.T) {
+
+		size := SubnetSize{Nodes: 500, MaxPodsPerNode: 110, Services: 1000}
+
+		// act
+		nodePrefixLength, _, _ := size.GetPrefixLength(TypeNode)
+		podPrefixLength, _, _ := size.GetPrefixLength(TypePod)
+		servicePrefixLength, _, _ := size.GetPrefixLength(TypeService)
+
+		assert.Equal(t, 23, nodePrefixLength)
+		assert.Equal(t, 15, podPrefixLength)
+		assert.Equal(t, 22, servicePrefixLength)
+	})
+
+	t.Run("ReturnsNotOkForTypesCountsDoNotApplyTo", func(t *testing.T) {
+
+		size := SubnetSize{Nodes: 500}
+
+		// act
+		_, ok, err := size.GetPrefixLength(TypeMaster)
+
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestGetClusterSize(t *testing.T) {
+
+	t.Run("ReturnsPrefixLengthsForNodesPodsAndServices", func(t *testing.T) {
+
+		// act
+		clusterSize, err := GetClusterSize(500, 110, 1000)
+
+		assert.Nil(t, err)
+		assert.Equal(t, ClusterSize{NodePrefixLength: 23, PodPrefixLength: 15, ServicePrefixLength: 22}, clusterSize)
+	})
+
+	t.Run("DefaultsMaxPodsPerNode", func(t *testing.T) {
+
+		// act
+		clusterSize, err := GetClusterSize(256, 0, 100)
+
+		assert.Nil(t, err)
+		assert.Equal(t, ClusterSize{NodePrefixLength: 23, PodPrefixLength: 16, ServicePrefixLength: 25}, clusterSize)
+	})
+
+	t.Run("ReturnsErrorIfNodesIsNotPositive", func(t *testing.T) {
+
+		// act
+		_, err := GetClusterSize(0, 110, 100)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Value 0 for nodes is invalid; it needs to be a positive number", err.Error())
+	})
+
+	t.Run("ReturnsErrorIfMaxPodsPerNodeIsOutOfRange", func(t *testing.T) {
+
+		// act
+		_, err := GetClusterSize(10, 4, 100)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGetNodePrefixLength(t *testing.T) {
+
+	t.Run("TakesReservedAddressesIntoAccount", func(t *testing.T) {
+
+		// act
+		prefixLength := GetNodePrefixLength(252)
+		prefixLengthWithReserved := GetNodePrefixLength(253)
+
+		assert.Equal(t, 24, prefixLength)
+		assert.Equal(t, 23, prefixLengthWithReserved)
+	})
+
+	t.Run("ReturnsSmallestRangeGKESupports", func(t *testing.T) {
+
+		// act
+		prefixLength := GetNodePrefixLength(1)
+
+		assert.Equal(t, 29, prefixLength)
+	})
+}
+
+func TestGetPodPrefixLength(t *testing.T) {
+
+	t.Run("DoublesMaxPodsPerNode", func(t *testing.T) {
+
+		// act
+		prefixLength, err := GetPodPrefixLength(256, 110)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 16, prefixLength)
+	})
+
+	t.Run("RoundsRangePerNodeUpToPowerOfTwo", func(t *testing.T) {
+
+		// act
+		prefixLength, err := GetPodPrefixLength(1024, 32)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 16, prefixLength)
+	})
+
+	t.Run("ReturnsErrorIfMaxPodsPerNodeIsOutOfRange", func(t *testing.T) {
+
+		// act
+		_, err := GetPodPrefixLength(10, 257)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Value 257 for max pods per node is invalid; it needs to be between 8 and 256", err.Error())
+	})
+}
+
+func TestGetServicePrefixLength(t *testing.T) {
+
+	t.Run("ReturnsRangeFittingServices", func(t *testing.T) {
+
+		// act
+		prefixLength := GetServicePrefixLength(1024)
+
+		assert.Equal(t, 22, prefixLength)
+	})
+}
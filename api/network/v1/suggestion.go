@@ -0,0 +1,8 @@
+package network
+
+type Suggestion struct {
+	Type        Type        `json:"type" yaml:"type"`
+	RangeType   RangeType   `json:"ip_cidr_range_type" yaml:"ip_cidr_range_type"`
+	CIDR        string      `json:"cidr" yaml:"cidr"`
+	RangeConfig RangeConfig `json:"range_config" yaml:"range_config"`
+}
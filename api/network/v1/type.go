@@ -1,5 +1,12 @@
 package network
 
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
 type Type string
 
 const (
@@ -11,3 +18,52 @@ const (
 
 	TypeUnknown Type = ""
 )
+
+var (
+	// BuiltInTypes are used when a config doesn't declare its own types
+	BuiltInTypes = []Type{TypeNode, TypePod, TypeService, TypeMaster, TypeOther}
+
+	typeNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+)
+
+// ParseType returns the type for value, or an error if it isn't a valid type name; whether the type is declared is checked when validating the config
+func ParseType(value string) (t Type, err error) {
+	if !typeNameRegex.MatchString(value) {
+		return TypeUnknown, fmt.Errorf("Value %q for field type is invalid; please use lowercase letters, digits, hyphens and underscores, starting with a letter", value)
+	}
+
+	return Type(value), nil
+}
+
+// UnmarshalJSON rejects invalid type names, so they aren't silently decoded as an unknown type
+func (t *Type) UnmarshalJSON(data []byte) (err error) {
+	var value string
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return
+	}
+
+	*t, err = ParseType(value)
+	return
+}
+
+// UnmarshalYAML rejects invalid type names, so they aren't silently decoded as an unknown type
+func (t *Type) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
+	var value string
+	err = unmarshal(&value)
+	if err != nil {
+		return
+	}
+
+	*t, err = ParseType(value)
+	return
+}
+
+// joinWithOr joins values like "a, b or c"
+func joinWithOr(values []string) string {
+	if len(values) < 2 {
+		return strings.Join(values, "")
+	}
+
+	return strings.Join(values[:len(values)-1], ", ") + " or " + values[len(values)-1]
+}
@@ -0,0 +1,69 @@
+package network
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeUnmarshalJSON(t *testing.T) {
+
+	t.Run("ReturnsDeclaredType", func(t *testing.T) {
+
+		var networkType Type
+
+		// act
+		err := json.Unmarshal([]byte(`"service"`), &networkType)
+
+		assert.Nil(t, err)
+		assert.Equal(t, TypeService, networkType)
+	})
+
+	t.Run("ReturnsCustomType", func(t *testing.T) {
+
+		var networkType Type
+
+		// act
+		err := json.Unmarshal([]byte(`"serverless-connector"`), &networkType)
+
+		assert.Nil(t, err)
+		assert.Equal(t, Type("serverless-connector"), networkType)
+	})
+
+	t.Run("ReturnsErrorForInvalidTypeName", func(t *testing.T) {
+
+		var networkType Type
+
+		// act
+		err := json.Unmarshal([]byte(`""`), &networkType)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Value \"\" for field type is invalid; please use lowercase letters, digits, hyphens and underscores, starting with a letter", err.Error())
+	})
+}
+
+func TestRangeTypeUnmarshalJSON(t *testing.T) {
+
+	t.Run("ReturnsDeclaredRangeType", func(t *testing.T) {
+
+		var rangeType RangeType
+
+		// act
+		err := json.Unmarshal([]byte(`"secondary"`), &rangeType)
+
+		assert.Nil(t, err)
+		assert.Equal(t, RangeTypeSecondary, rangeType)
+	})
+
+	t.Run("ReturnsErrorForUndeclaredRangeType", func(t *testing.T) {
+
+		var rangeType RangeType
+
+		// act
+		err := json.Unmarshal([]byte(`""`), &rangeType)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Value \"\" for field ip_cidr_range_type is invalid; please set to primary or secondary", err.Error())
+	})
+}
@@ -12,7 +12,9 @@ import (
 	"github.com/rs/zerolog/log"
 	"golang.org/x/oauth2/google"
 	crmv1 "google.golang.org/api/cloudresourcemanager/v1"
+	crmv2 "google.golang.org/api/cloudresourcemanager/v2"
 	computev1 "google.golang.org/api/compute/v1"
+	containerv1 "google.golang.org/api/container/v1"
 	"google.golang.org/api/googleapi"
 	iamv1 "google.golang.org/api/iam/v1"
 )
@@ -40,9 +42,24 @@ var (
 //go:generate mockgen -package=gcp -destination ./mock.go -source=client.go
 type Client interface {
 	GetProjectByLabels(ctx context.Context, filters []string) (projects []*crmv1.Project, err error)
+	GetProjectsByID(ctx context.Context, projectIDs []string) (projects []*crmv1.Project, err error)
+	GetFolderIDs(ctx context.Context, parent string) (folderIDs []string, err error)
+	GetSharedVPCHostProjects(ctx context.Context, projects []*crmv1.Project) (hostProjects []*crmv1.Project, err error)
 	GetProjectNetworks(ctx context.Context, projects []*crmv1.Project) (networks []*computev1.Network, err error)
 	GetProjectSubnetworks(ctx context.Context, projects []*crmv1.Project) (subnetworks []*computev1.Subnetwork, err error)
 	GetProjectRoutes(ctx context.Context, projects []*crmv1.Project) (routes []*computev1.Route, err error)
+	GetNetworkPeeringRoutes(ctx context.Context, networks []*computev1.Network) (peeringRoutes []*PeeringRoute, err error)
+	GetProjectInternalAddresses(ctx context.Context, projects []*crmv1.Project) (addresses []*computev1.Address, err error)
+	GetProjectClusters(ctx context.Context, projects []*crmv1.Project) (clusters []*containerv1.Cluster, err error)
+}
+
+// PeeringRoute is a route imported into Network over the vpc peering PeeringName with PeerNetwork
+type PeeringRoute struct {
+	*computev1.ExchangedPeeringRoute
+
+	Network     string
+	PeeringName string
+	PeerNetwork string
 }
 
 // NewClient returns a new gcp.Client
@@ -64,17 +81,31 @@ func NewClient(ctx context.Context, concurrency int) (Client, error) {
 		return nil, err
 	}
 
+	crmv2Service, err := crmv2.New(googleClient)
+	if err != nil {
+		return nil, err
+	}
+
+	containerv1Service, err := containerv1.New(googleClient)
+	if err != nil {
+		return nil, err
+	}
+
 	return &client{
-		computev1Service: computev1Service,
-		crmv1Service:     crmv1Service,
+		computev1Service:   computev1Service,
+		crmv1Service:       crmv1Service,
+		crmv2Service:       crmv2Service,
+		containerv1Service: containerv1Service,
 
 		concurrency: concurrency,
 	}, nil
 }
 
 type client struct {
-	computev1Service *computev1.Service
-	crmv1Service     *crmv1.Service
+	computev1Service   *computev1.Service
+	crmv1Service       *crmv1.Service
+	crmv2Service       *crmv2.Service
+	containerv1Service *containerv1.Service
 
 	concurrency int
 }
@@ -125,6 +156,179 @@ func (c *client) GetProjectByLabels(ctx context.Context, filters []string) (proj
 	return
 }
 
+func (c *client) GetProjectsByID(ctx context.Context, projectIDs []string) (projects []*crmv1.Project, err error) {
+
+	log.Info().Msgf("Retrieving projects %v...", projectIDs)
+
+	projects = make([]*crmv1.Project, 0)
+
+	for _, projectID := range projectIDs {
+		var project *crmv1.Project
+		err = c.substituteErrorsWithPredefinedErrors(foundation.Retry(func() error {
+			project, err = c.crmv1Service.Projects.Get(projectID).Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			return nil
+		}, c.getRetryOptions()...))
+		if err != nil {
+			return projects, fmt.Errorf("Can't get project %v: %w", projectID, err)
+		}
+
+		if project.LifecycleState != "ACTIVE" {
+			log.Warn().Msgf("Project %v is %v, skipping it", projectID, project.LifecycleState)
+			continue
+		}
+
+		projects = append(projects, project)
+	}
+
+	return
+}
+
+// GetFolderIDs returns the ids of all folders under parent, which is either organizations/{id} or folders/{id}, recursively through sub-folders
+func (c *client) GetFolderIDs(ctx context.Context, parent string) (folderIDs []string, err error) {
+
+	log.Info().Msgf("Retrieving folders under %v...", parent)
+
+	parents := []string{parent}
+	for len(parents) > 0 {
+		currentParent := parents[0]
+		parents = parents[1:]
+
+		nextPageToken := ""
+		for {
+			// retrieving folders for parent (by page)
+			var resp *crmv2.ListFoldersResponse
+			err = c.substituteErrorsWithPredefinedErrors(foundation.Retry(func() error {
+				listCall := c.crmv2Service.Folders.List().Parent(currentParent)
+				if nextPageToken != "" {
+					listCall.PageToken(nextPageToken)
+				}
+				resp, err = listCall.Context(ctx).Do()
+				if err != nil {
+					return err
+				}
+				return nil
+			}, c.getRetryOptions()...))
+			if err != nil {
+				return folderIDs, fmt.Errorf("Can't get folders under %v: %w", currentParent, err)
+			}
+
+			for _, f := range resp.Folders {
+				if f.LifecycleState != "ACTIVE" {
+					continue
+				}
+				folderIDs = append(folderIDs, strings.TrimPrefix(f.Name, "folders/"))
+				parents = append(parents, f.Name)
+			}
+
+			if resp.NextPageToken == "" {
+				break
+			}
+			nextPageToken = resp.NextPageToken
+		}
+	}
+
+	log.Debug().Msgf("Retrieved %v folders under %v", len(folderIDs), parent)
+
+	return
+}
+
+func (c *client) getSharedVPCHostProject(ctx context.Context, projectID string) (hostProjectID string, err error) {
+
+	log.Debug().Msgf("Retrieving shared vpc host project for project %v...", projectID)
+
+	var resp *computev1.Project
+	err = c.substituteErrorsWithPredefinedErrors(foundation.Retry(func() error {
+		resp, err = c.computev1Service.Projects.GetXpnHost(projectID).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		return nil
+	}, c.getRetryOptions()...))
+	if err != nil && !errors.Is(err, ErrAPIForbidden) {
+		return "", fmt.Errorf("Can't get shared vpc host project for project id %v: %w", projectID, err)
+	}
+	if err != nil && errors.Is(err, ErrAPIForbidden) {
+		return "", nil
+	}
+
+	// projects that aren't attached to a shared vpc host return an empty project
+	return resp.Name, nil
+}
+
+// GetSharedVPCHostProjects returns the shared vpc host projects the projects are attached to as service projects, leaving out hosts that are in projects already
+func (c *client) GetSharedVPCHostProjects(ctx context.Context, projects []*crmv1.Project) (hostProjects []*crmv1.Project, err error) {
+
+	// http://jmoiron.net/blog/limiting-concurrency-in-go/
+	semaphore := make(chan bool, c.concurrency)
+	cancelled := false
+
+	resultChannel := make(chan struct {
+		HostProjectID string
+		Err           error
+	}, len(projects))
+
+	for _, p := range projects {
+		select {
+		// try to fill semaphore up to it's full size otherwise wait for a routine to finish
+		case semaphore <- true:
+			go func(ctx context.Context, p *crmv1.Project) {
+				// lower semaphore once the routine's finished, making room for another one to start
+				defer func() { <-semaphore }()
+
+				hostProjectID, err := c.getSharedVPCHostProject(ctx, p.ProjectId)
+
+				resultChannel <- struct {
+					HostProjectID string
+					Err           error
+				}{hostProjectID, err}
+			}(ctx, p)
+
+		case <-ctx.Done():
+			log.Info().Msg("User has canceled execution, stopping retrieval of shared vpc host projects...")
+			cancelled = true
+		}
+		if cancelled {
+			log.Info().Msg("User has canceled execution, waiting for pending retrieval of shared vpc host projects to finish...")
+			break
+		}
+	}
+
+	// try to fill semaphore up to it's full size which only succeeds if all routines have finished or execution has been canceled
+	for i := 0; i < cap(semaphore); i++ {
+		semaphore <- true
+	}
+
+	if cancelled {
+		log.Info().Msg("User has canceled execution, checking retrieved shared vpc host projects...")
+	}
+
+	projectIDs := map[string]bool{}
+	for _, p := range projects {
+		projectIDs[p.ProjectId] = true
+	}
+
+	// check for errors and aggregate all host projects that aren't in projects yet
+	close(resultChannel)
+	for r := range resultChannel {
+		if r.Err != nil {
+			err = r.Err
+			return
+		}
+		if r.HostProjectID == "" || projectIDs[r.HostProjectID] {
+			continue
+		}
+		projectIDs[r.HostProjectID] = true
+		hostProjects = append(hostProjects, &crmv1.Project{ProjectId: r.HostProjectID})
+	}
+
+	log.Debug().Msgf("Retrieved %v shared vpc host projects for %v projects", len(hostProjects), len(projects))
+
+	return
+}
+
 func (c *client) getProjectNetworks(ctx context.Context, projectID string) (networks []*computev1.Network, err error) {
 	if projectID == "" {
 		return nil, fmt.Errorf("GetProjectNetworks argument projectID is empty")
@@ -425,6 +629,458 @@ func (c *client) GetProjectRoutes(ctx context.Context, projects []*crmv1.Project
 	return
 }
 
+func (c *client) getProjectInternalAddresses(ctx context.Context, projectID string) (addresses []*computev1.Address, err error) {
+
+	log.Info().Msgf("Retrieving internal addresses for project %v...", projectID)
+
+	filter := `addressType = "INTERNAL"`
+
+	// global addresses hold the ranges allocated for private service access with purpose VPC_PEERING
+	nextPageToken := ""
+	for {
+		var resp *computev1.AddressList
+		err = c.substituteErrorsWithPredefinedErrors(foundation.Retry(func() error {
+
+			listCall := c.computev1Service.GlobalAddresses.List(projectID).Filter(filter)
+			if nextPageToken != "" {
+				listCall.PageToken(nextPageToken)
+			}
+			resp, err = listCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			return nil
+		}, c.getRetryOptions()...))
+		if err != nil && !errors.Is(err, ErrAPIForbidden) {
+			return addresses, fmt.Errorf("Can't get project global addresses for project id %v: %w", projectID, err)
+		}
+		if err != nil && errors.Is(err, ErrAPIForbidden) {
+			return addresses, nil
+		}
+
+		addresses = append(addresses, resp.Items...)
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		nextPageToken = resp.NextPageToken
+	}
+
+	nextPageToken = ""
+	for {
+		var resp *computev1.AddressAggregatedList
+		err = c.substituteErrorsWithPredefinedErrors(foundation.Retry(func() error {
+
+			listCall := c.computev1Service.Addresses.AggregatedList(projectID).Filter(filter)
+			if nextPageToken != "" {
+				listCall.PageToken(nextPageToken)
+			}
+			resp, err = listCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			return nil
+		}, c.getRetryOptions()...))
+		if err != nil && !errors.Is(err, ErrAPIForbidden) {
+			return addresses, fmt.Errorf("Can't get project regional addresses for project id %v: %w", projectID, err)
+		}
+		if err != nil && errors.Is(err, ErrAPIForbidden) {
+			return addresses, nil
+		}
+
+		for _, v := range resp.Items {
+			if v.Addresses != nil && len(v.Addresses) > 0 {
+				addresses = append(addresses, v.Addresses...)
+			}
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		nextPageToken = resp.NextPageToken
+	}
+
+	log.Debug().Msgf("Retrieved %v internal addresses for project %v", len(addresses), projectID)
+
+	return
+}
+
+func (c *client) GetProjectInternalAddresses(ctx context.Context, projects []*crmv1.Project) (addresses []*computev1.Address, err error) {
+
+	// http://jmoiron.net/blog/limiting-concurrency-in-go/
+	semaphore := make(chan bool, c.concurrency)
+	cancelled := false
+
+	resultChannel := make(chan struct {
+		Addresses []*computev1.Address
+		Err       error
+	}, len(projects))
+
+	for _, p := range projects {
+		select {
+		// try to fill semaphore up to it's full size otherwise wait for a routine to finish
+		case semaphore <- true:
+			go func(ctx context.Context, p *crmv1.Project) {
+				// lower semaphore once the routine's finished, making room for another one to start
+				defer func() { <-semaphore }()
+
+				addresses, err := c.getProjectInternalAddresses(ctx, p.ProjectId)
+
+				resultChannel <- struct {
+					Addresses []*computev1.Address
+					Err       error
+				}{addresses, err}
+			}(ctx, p)
+
+		case <-ctx.Done():
+			log.Info().Msg("User has canceled execution, stopping retrieval of addresses...")
+			cancelled = true
+		}
+		if cancelled {
+			log.Info().Msg("User has canceled execution, waiting for pending retrieval of addresses to finish...")
+			break
+		}
+	}
+
+	// try to fill semaphore up to it's full size which only succeeds if all routines have finished or execution has been canceled
+	for i := 0; i < cap(semaphore); i++ {
+		semaphore <- true
+	}
+
+	if cancelled {
+		log.Info().Msg("User has canceled execution, checking retrieved addresses...")
+	}
+
+	// check for errors and aggregate all addresses
+	close(resultChannel)
+	for r := range resultChannel {
+		if r.Err != nil {
+			err = r.Err
+			return
+		}
+		addresses = append(addresses, r.Addresses...)
+	}
+
+	return
+}
+
+func (c *client) getProjectClusters(ctx context.Context, projectID string) (clusters []*containerv1.Cluster, err error) {
+
+	log.Info().Msgf("Retrieving clusters for project %v...", projectID)
+
+	var resp *containerv1.ListClustersResponse
+	err = c.substituteErrorsWithPredefinedErrors(foundation.Retry(func() error {
+		// list clusters in all zones and regions at once
+		resp, err = c.containerv1Service.Projects.Locations.Clusters.List(fmt.Sprintf("projects/%v/locations/-", projectID)).Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		return nil
+	}, c.getRetryOptions()...))
+	if err != nil && !errors.Is(err, ErrAPIForbidden) {
+		return clusters, fmt.Errorf("Can't get project clusters for project id %v: %w", projectID, err)
+	}
+	if err != nil && errors.Is(err, ErrAPIForbidden) {
+		return clusters, nil
+	}
+
+	if len(resp.MissingZones) > 0 {
+		log.Warn().Msgf("Clusters for project %v could not be retrieved for zones %v", projectID, resp.MissingZones)
+	}
+
+	clusters = resp.Clusters
+
+	log.Debug().Msgf("Retrieved %v clusters for project %v", len(clusters), projectID)
+
+	return
+}
+
+func (c *client) GetProjectClusters(ctx context.Context, projects []*crmv1.Project) (clusters []*containerv1.Cluster, err error) {
+
+	// http://jmoiron.net/blog/limiting-concurrency-in-go/
+	semaphore := make(chan bool, c.concurrency)
+	cancelled := false
+
+	resultChannel := make(chan struct {
+		Clusters []*containerv1.Cluster
+		Err      error
+	}, len(projects))
+
+	for _, p := range projects {
+		select {
+		// try to fill semaphore up to it's full size otherwise wait for a routine to finish
+		case semaphore <- true:
+			go func(ctx context.Context, p *crmv1.Project) {
+				// lower semaphore once the routine's finished, making room for another one to start
+				defer func() { <-semaphore }()
+
+				clusters, err := c.getProjectClusters(ctx, p.ProjectId)
+
+				resultChannel <- struct {
+					Clusters []*containerv1.Cluster
+					Err      error
+				}{clusters, err}
+			}(ctx, p)
+
+		case <-ctx.Done():
+			log.Info().Msg("User has canceled execution, stopping retrieval of clusters...")
+			cancelled = true
+		}
+		if cancelled {
+			log.Info().Msg("User has canceled execution, waiting for pending retrieval of clusters to finish...")
+			break
+		}
+	}
+
+	// try to fill semaphore up to it's full size which only succeeds if all routines have finished or execution has been canceled
+	for i := 0; i < cap(semaphore); i++ {
+		semaphore <- true
+	}
+
+	if cancelled {
+		log.Info().Msg("User has canceled execution, checking retrieved clusters...")
+	}
+
+	// check for errors and aggregate all clusters
+	close(resultChannel)
+	for r := range resultChannel {
+		if r.Err != nil {
+			err = r.Err
+			return
+		}
+		clusters = append(clusters, r.Clusters...)
+	}
+
+	return
+}
+
+func (c *client) getProjectRegions(ctx context.Context, projectID string) (regions []string, err error) {
+
+	log.Debug().Msgf("Retrieving regions for project %v...", projectID)
+
+	nextPageToken := ""
+	for {
+		var resp *computev1.RegionList
+		err = c.substituteErrorsWithPredefinedErrors(foundation.Retry(func() error {
+			listCall := c.computev1Service.Regions.List(projectID)
+			if nextPageToken != "" {
+				listCall.PageToken(nextPageToken)
+			}
+			resp, err = listCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			return nil
+		}, c.getRetryOptions()...))
+		if err != nil {
+			return regions, fmt.Errorf("Can't get regions for project id %v: %w", projectID, err)
+		}
+		for _, r := range resp.Items {
+			regions = append(regions, r.Name)
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		nextPageToken = resp.NextPageToken
+	}
+
+	log.Debug().Msgf("Retrieved %v regions for project %v", len(regions), projectID)
+
+	return
+}
+
+func (c *client) getNetworkPeeringRoutes(ctx context.Context, network *computev1.Network) (peeringRoutes []*PeeringRoute, err error) {
+
+	projectID := GetProjectFromSelfLink(network.SelfLink)
+
+	// peering routes are listed per region, so use the regions the network has subnetworks in
+	regions := []string{}
+	for _, sn := range network.Subnetworks {
+		region := getRegionFromSelfLink(sn)
+		if region != "" && !contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+
+	// a network without subnetworks can still import routes, so fall back to every region available to the project
+	if len(regions) == 0 {
+		regions, err = c.getProjectRegions(ctx, projectID)
+		if err != nil && !errors.Is(err, ErrAPIForbidden) {
+			return
+		}
+		if err != nil && errors.Is(err, ErrAPIForbidden) {
+			log.Warn().Msgf("Regions for project %v can't be retrieved, skipping peering routes of network %v", projectID, network.Name)
+			return peeringRoutes, nil
+		}
+	}
+
+	for _, p := range network.Peerings {
+		if p.State != "ACTIVE" {
+			continue
+		}
+
+		routes, err := c.getPeeringRoutes(ctx, projectID, network, p, regions)
+		if err != nil && !errors.Is(err, ErrAPIForbidden) {
+			return peeringRoutes, err
+		}
+		if err != nil && errors.Is(err, ErrAPIForbidden) {
+			log.Warn().Msgf("Routes imported over peering %v of network %v in project %v can't be retrieved, skipping the peering", p.Name, network.Name, projectID)
+			continue
+		}
+		peeringRoutes = append(peeringRoutes, routes...)
+	}
+
+	log.Debug().Msgf("Retrieved %v peering routes for network %v in project %v", len(peeringRoutes), network.Name, projectID)
+
+	return
+}
+
+func (c *client) getPeeringRoutes(ctx context.Context, projectID string, network *computev1.Network, peering *computev1.NetworkPeering, regions []string) (peeringRoutes []*PeeringRoute, err error) {
+
+	log.Info().Msgf("Retrieving routes imported over peering %v of network %v in project %v...", peering.Name, network.Name, projectID)
+
+	// subnet routes of the peer are returned for every region, so only keep each destination range once
+	destRanges := map[string]bool{}
+	for _, region := range regions {
+		nextPageToken := ""
+		for {
+			var resp *computev1.ExchangedPeeringRoutesList
+			err = c.substituteErrorsWithPredefinedErrors(foundation.Retry(func() error {
+				listCall := c.computev1Service.Networks.ListPeeringRoutes(projectID, network.Name).PeeringName(peering.Name).Direction("INCOMING").Region(region)
+				if nextPageToken != "" {
+					listCall.PageToken(nextPageToken)
+				}
+				resp, err = listCall.Context(ctx).Do()
+				if err != nil {
+					return err
+				}
+				return nil
+			}, c.getRetryOptions()...))
+			if err != nil {
+				return nil, fmt.Errorf("Can't get peering routes for peering %v of network %v in project id %v: %w", peering.Name, network.Name, projectID, err)
+			}
+
+			for _, r := range resp.Items {
+				if destRanges[r.DestRange] {
+					continue
+				}
+				destRanges[r.DestRange] = true
+				peeringRoutes = append(peeringRoutes, &PeeringRoute{
+					ExchangedPeeringRoute: r,
+					Network:               network.SelfLink,
+					PeeringName:           peering.Name,
+					PeerNetwork:           peering.Network,
+				})
+			}
+
+			if resp.NextPageToken == "" {
+				break
+			}
+			nextPageToken = resp.NextPageToken
+		}
+	}
+
+	return
+}
+
+func (c *client) GetNetworkPeeringRoutes(ctx context.Context, networks []*computev1.Network) (peeringRoutes []*PeeringRoute, err error) {
+
+	// http://jmoiron.net/blog/limiting-concurrency-in-go/
+	semaphore := make(chan bool, c.concurrency)
+	cancelled := false
+
+	resultChannel := make(chan struct {
+		PeeringRoutes []*PeeringRoute
+		Err           error
+	}, len(networks))
+
+	for _, n := range networks {
+		if len(n.Peerings) == 0 {
+			continue
+		}
+
+		select {
+		// try to fill semaphore up to it's full size otherwise wait for a routine to finish
+		case semaphore <- true:
+			go func(ctx context.Context, n *computev1.Network) {
+				// lower semaphore once the routine's finished, making room for another one to start
+				defer func() { <-semaphore }()
+
+				peeringRoutes, err := c.getNetworkPeeringRoutes(ctx, n)
+
+				resultChannel <- struct {
+					PeeringRoutes []*PeeringRoute
+					Err           error
+				}{peeringRoutes, err}
+			}(ctx, n)
+
+		case <-ctx.Done():
+			log.Info().Msg("User has canceled execution, stopping retrieval of peering routes...")
+			cancelled = true
+		}
+		if cancelled {
+			log.Info().Msg("User has canceled execution, waiting for pending retrieval of peering routes to finish...")
+			break
+		}
+	}
+
+	// try to fill semaphore up to it's full size which only succeeds if all routines have finished or execution has been canceled
+	for i := 0; i < cap(semaphore); i++ {
+		semaphore <- true
+	}
+
+	if cancelled {
+		log.Info().Msg("User has canceled execution, checking retrieved peering routes...")
+	}
+
+	// check for errors and aggregate all peering routes
+	close(resultChannel)
+	for r := range resultChannel {
+		if r.Err != nil {
+			err = r.Err
+			return
+		}
+		peeringRoutes = append(peeringRoutes, r.PeeringRoutes...)
+	}
+
+	return
+}
+
+// GetProjectFromSelfLink returns the project id from urls like https://www.googleapis.com/compute/v1/projects/{project}/global/networks/{name}
+func GetProjectFromSelfLink(selfLink string) string {
+	segments := strings.Split(selfLink, "/")
+	for i, s := range segments {
+		if s == "projects" && i+1 < len(segments) {
+			return segments[i+1]
+		}
+	}
+
+	return ""
+}
+
+// getRegionFromSelfLink returns the region from urls like https://www.googleapis.com/compute/v1/projects/{project}/regions/{region}/subnetworks/{name}
+func getRegionFromSelfLink(selfLink string) string {
+	segments := strings.Split(selfLink, "/")
+	for i, s := range segments {
+		if s == "regions" && i+1 < len(segments) {
+			return segments[i+1]
+		}
+	}
+
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (c *client) isRetryableErrorCustomOption() foundation.RetryOption {
 	return func(c *foundation.RetryConfig) {
 		c.IsRetryableError = func(err error) bool {
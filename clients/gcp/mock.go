@@ -9,6 +9,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v1"
 	compute "google.golang.org/api/compute/v1"
+	container "google.golang.org/api/container/v1"
 	reflect "reflect"
 )
 
@@ -50,6 +51,51 @@ func (mr *MockClientMockRecorder) GetProjectByLabels(ctx, filters interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectByLabels", reflect.TypeOf((*MockClient)(nil).GetProjectByLabels), ctx, filters)
 }
 
+// GetProjectsByID mocks base method
+func (m *MockClient) GetProjectsByID(ctx context.Context, projectIDs []string) ([]*cloudresourcemanager.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectsByID", ctx, projectIDs)
+	ret0, _ := ret[0].([]*cloudresourcemanager.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectsByID indicates an expected call of GetProjectsByID
+func (mr *MockClientMockRecorder) GetProjectsByID(ctx, projectIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectsByID", reflect.TypeOf((*MockClient)(nil).GetProjectsByID), ctx, projectIDs)
+}
+
+// GetFolderIDs mocks base method
+func (m *MockClient) GetFolderIDs(ctx context.Context, parent string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFolderIDs", ctx, parent)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFolderIDs indicates an expected call of GetFolderIDs
+func (mr *MockClientMockRecorder) GetFolderIDs(ctx, parent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFolderIDs", reflect.TypeOf((*MockClient)(nil).GetFolderIDs), ctx, parent)
+}
+
+// GetSharedVPCHostProjects mocks base method
+func (m *MockClient) GetSharedVPCHostProjects(ctx context.Context, projects []*cloudresourcemanager.Project) ([]*cloudresourcemanager.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedVPCHostProjects", ctx, projects)
+	ret0, _ := ret[0].([]*cloudresourcemanager.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedVPCHostProjects indicates an expected call of GetSharedVPCHostProjects
+func (mr *MockClientMockRecorder) GetSharedVPCHostProjects(ctx, projects interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedVPCHostProjects", reflect.TypeOf((*MockClient)(nil).GetSharedVPCHostProjects), ctx, projects)
+}
+
 // GetProjectNetworks mocks base method
 func (m *MockClient) GetProjectNetworks(ctx context.Context, projects []*cloudresourcemanager.Project) ([]*compute.Network, error) {
 	m.ctrl.T.Helper()
@@ -94,3 +140,48 @@ func (mr *MockClientMockRecorder) GetProjectRoutes(ctx, projects interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectRoutes", reflect.TypeOf((*MockClient)(nil).GetProjectRoutes), ctx, projects)
 }
+
+// GetNetworkPeeringRoutes mocks base method
+func (m *MockClient) GetNetworkPeeringRoutes(ctx context.Context, networks []*compute.Network) ([]*PeeringRoute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkPeeringRoutes", ctx, networks)
+	ret0, _ := ret[0].([]*PeeringRoute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetworkPeeringRoutes indicates an expected call of GetNetworkPeeringRoutes
+func (mr *MockClientMockRecorder) GetNetworkPeeringRoutes(ctx, networks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkPeeringRoutes", reflect.TypeOf((*MockClient)(nil).GetNetworkPeeringRoutes), ctx, networks)
+}
+
+// GetProjectInternalAddresses mocks base method
+func (m *MockClient) GetProjectInternalAddresses(ctx context.Context, projects []*cloudresourcemanager.Project) ([]*compute.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectInternalAddresses", ctx, projects)
+	ret0, _ := ret[0].([]*compute.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectInternalAddresses indicates an expected call of GetProjectInternalAddresses
+func (mr *MockClientMockRecorder) GetProjectInternalAddresses(ctx, projects interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectInternalAddresses", reflect.TypeOf((*MockClient)(nil).GetProjectInternalAddresses), ctx, projects)
+}
+
+// GetProjectClusters mocks base method
+func (m *MockClient) GetProjectClusters(ctx context.Context, projects []*cloudresourcemanager.Project) ([]*container.Cluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProjectClusters", ctx, projects)
+	ret0, _ := ret[0].([]*container.Cluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectClusters indicates an expected call of GetProjectClusters
+func (mr *MockClientMockRecorder) GetProjectClusters(ctx, projects interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectClusters", reflect.TypeOf((*MockClient)(nil).GetProjectClusters), ctx, projects)
+}
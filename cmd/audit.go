@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
+	"github.com/estafette/estafette-gcp-network-planner/services/planner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	// command-specific flags
+	addProjectFilterFlags(auditCmd)
+	auditCmd.Flags().StringVarP(&outputFormat, "output", "o", outputFormatTable, "Output format for the overlapping ranges: json, yaml or table")
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report subnetworks, secondary ranges and routes that overlap across projects or networks; exits non-zero when any are found",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		// fail early on unsupported output format
+		err := validateReportOutputFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+
+		// init gcp client
+		gcpClient, err := gcp.NewClient(cmd.Context(), concurrency)
+		if err != nil {
+			return err
+		}
+
+		// init planner service
+		plannerService, err := planner.NewService(cmd.Context(), gcpClient, nil, configFilePath)
+		if err != nil {
+			return err
+		}
+
+		overlaps, err := plannerService.Audit(cmd.Context(), getProjectFilter())
+		if err != nil {
+			return err
+		}
+
+		err = printOverlaps(cmd.OutOrStdout(), outputFormat, overlaps)
+		if err != nil {
+			return err
+		}
+
+		if len(overlaps) > 0 {
+			// the overlaps are the outcome of the audit, not a usage error
+			cmd.SilenceUsage = true
+			return fmt.Errorf("Found %v overlapping ranges across projects or networks", len(overlaps))
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/estafette/estafette-gcp-network-planner/services/planner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect range config files",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [config-file...]",
+	Short: "Validate config files without talking to GCP; exits non-zero when any of them has errors",
+	Long:  "Validate config files without talking to GCP. Without arguments the file set with --config-file, or the embedded config if that's empty, is validated. Warnings and errors are printed with the json path of the field they apply to, like range_configs[2].subnet_mask.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		paths := args
+		if len(paths) == 0 {
+			paths = []string{configFilePath}
+		}
+
+		invalidFiles := 0
+		for _, path := range paths {
+			name := path
+			if name == "" {
+				name = "embedded config"
+			}
+
+			// init planner service without gcp client, since loading the config doesn't need it
+			plannerService, err := planner.NewService(cmd.Context(), nil, nil, path)
+			if err != nil {
+				return err
+			}
+
+			config, err := plannerService.LoadConfig(cmd.Context())
+			if err != nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "%v: error: %v\n", name, err)
+				invalidFiles++
+				continue
+			}
+
+			valid, warnings, errors := config.Validate()
+			for _, w := range warnings {
+				fmt.Fprintf(cmd.OutOrStdout(), "%v: warning: %v\n", name, w)
+			}
+			for _, e := range errors {
+				fmt.Fprintf(cmd.OutOrStdout(), "%v: error: %v\n", name, e)
+			}
+
+			if !valid {
+				invalidFiles++
+			}
+		}
+
+		if invalidFiles > 0 {
+			// invalid config files are the outcome of the validation, not a usage error
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%v of %v config files are not valid", invalidFiles, len(paths))
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
+	"github.com/estafette/estafette-gcp-network-planner/services/planner"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	// command-specific flags
+	addProjectFilterFlags(explainCmd)
+	addSelectorFlags(explainCmd)
+	explainCmd.Flags().StringSliceVar(&networkTypes, "type", []string{}, "Network types to explain; defaults to all types declared in the config")
+	explainCmd.Flags().StringVar(&allocationStrategyValue, "allocation-strategy", "", "Allocation strategy for picking free ranges: first-fit, best-fit or last-fit; overrides the allocation_strategy of the range configs, which defaults to first-fit")
+	explainCmd.Flags().StringVarP(&outputFormat, "output", "o", outputFormatTable, "Output format for the explanations: json, yaml or table")
+}
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain which existing subnetworks, secondary ranges, routes and reservations block candidate network ranges",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		// fail early on unsupported output format
+		err := validateReportOutputFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+
+		allocationStrategy, err := networkv1.ParseAllocationStrategy(allocationStrategyValue)
+		if err != nil {
+			return err
+		}
+
+		// init gcp client
+		gcpClient, err := gcp.NewClient(cmd.Context(), concurrency)
+		if err != nil {
+			return err
+		}
+
+		// init reservation store
+		reservationStore, err := newReservationStore(cmd.Context(), false)
+		if err != nil {
+			return err
+		}
+
+		// init planner service
+		plannerService, err := planner.NewService(cmd.Context(), gcpClient, reservationStore, configFilePath, planner.WithAllocationStrategy(allocationStrategy))
+		if err != nil {
+			return err
+		}
+
+		selector := getSelector()
+
+		types, err := parseNetworkTypes(networkTypes)
+		if err != nil {
+			return err
+		}
+
+		explanations, err := plannerService.Explain(cmd.Context(), getProjectFilter(), selector, types...)
+		if err != nil {
+			return err
+		}
+
+		return printExplanations(cmd.OutOrStdout(), outputFormat, explanations)
+	},
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/estafette/estafette-gcp-network-planner/services/planner"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	outputFormatJSON  = "json"
+	outputFormatYAML  = "yaml"
+	outputFormatTable = "table"
+	outputFormatEnv   = "env"
+)
+
+var (
+	outputFormats = []string{outputFormatJSON, outputFormatYAML, outputFormatTable, outputFormatEnv}
+
+	envVarInvalidCharsRegex = regexp.MustCompile(`[^A-Z0-9_]+`)
+)
+
+func validateOutputFormat(format string) error {
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Output format %v is not supported; please set to %v", format, strings.Join(outputFormats, ", "))
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func printYAML(w io.Writer, v interface{}) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(w, string(data))
+	return err
+}
+
+func printSuggestions(w io.Writer, format string, suggestions []networkv1.Suggestion) error {
+	switch format {
+	case outputFormatJSON:
+		return printJSON(w, suggestions)
+
+	case outputFormatYAML:
+		return printYAML(w, suggestions)
+
+	case outputFormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TYPE\tRANGE TYPE\tCIDR\tNETWORK\tCOMMENT")
+		for _, s := range suggestions {
+			fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\n", s.Type, s.RangeType, s.CIDR, s.RangeConfig.NetworkCIDR, s.RangeConfig.Comment)
+		}
+		return tw.Flush()
+
+	case outputFormatEnv:
+		// number env vars if there's more than one suggestion per type
+		totalPerType := map[networkv1.Type]int{}
+		for _, s := range suggestions {
+			totalPerType[s.Type]++
+		}
+
+		indexPerType := map[networkv1.Type]int{}
+		for _, s := range suggestions {
+			indexPerType[s.Type]++
+			name := getEnvVarName(s)
+			if totalPerType[s.Type] > 1 {
+				name = fmt.Sprintf("%v_%v", name, indexPerType[s.Type])
+			}
+
+			_, err := fmt.Fprintf(w, "%v=%v\n", name, s.CIDR)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return validateOutputFormat(format)
+}
+
+func printReservations(w io.Writer, format string, reservations []networkv1.Reservation) error {
+	switch format {
+	case outputFormatJSON:
+		return printJSON(w, reservations)
+
+	case outputFormatYAML:
+		return printYAML(w, reservations)
+
+	case outputFormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CIDR\tTYPE\tOWNER\tRESERVED AT\tEXPIRES AT\tCOMMENT")
+		for _, r := range reservations {
+			expiresAt := ""
+			if r.ExpiresAt != nil {
+				expiresAt = r.ExpiresAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\n", r.CIDR, r.Type, r.Owner, r.ReservedAt.Format(time.RFC3339), expiresAt, r.Comment)
+		}
+		return tw.Flush()
+
+	case outputFormatEnv:
+		for i, r := range reservations {
+			_, err := fmt.Fprintf(w, "RESERVATION_%v_CIDR=%v\n", i+1, r.CIDR)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return validateOutputFormat(format)
+}
+
+func validateReportOutputFormat(format string) error {
+	if format == outputFormatEnv {
+		return fmt.Errorf("Output format %v is not supported for reports; please set to %v, %v or %v", format, outputFormatJSON, outputFormatYAML, outputFormatTable)
+	}
+
+	return validateOutputFormat(format)
+}
+
+func printExplanations(w io.Writer, format string, explanations []planner.Explanation) error {
+	switch format {
+	case outputFormatJSON:
+		return printJSON(w, explanations)
+
+	case outputFormatYAML:
+		return printYAML(w, explanations)
+
+	case outputFormatTable:
+		for i, e := range explanations {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+
+			fmt.Fprintf(w, "Type %v (%v range) in %v split into /%v ranges: %v of %v candidates are free\n", e.Type, e.RangeConfig.RangeType, e.RangeConfig.NetworkCIDR, e.RangeConfig.SubnetMask, e.FreeCandidates, e.TotalCandidates)
+			if e.Suggestion != "" {
+				fmt.Fprintf(w, "Selected candidate using %v allocation: %v\n", e.AllocationStrategy, e.Suggestion)
+			} else {
+				fmt.Fprintln(w, "No free candidates left")
+			}
+
+			if len(e.BlockedCandidates) == 0 {
+				continue
+			}
+
+			fmt.Fprintln(w)
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "FIRST CANDIDATE\tLAST CANDIDATE\tCANDIDATES\tBLOCKED BY\tNAME\tPROJECT\tREGION\tNETWORK\tPEER NETWORK\tCIDR")
+			for _, b := range e.BlockedCandidates {
+				for j, ur := range b.BlockedBy {
+					// only print the candidates for the first used range blocking them
+					if j == 0 {
+						fmt.Fprintf(tw, "%v\t%v\t%v\t", b.FirstCIDR, b.LastCIDR, b.Count)
+					} else {
+						fmt.Fprint(tw, "\t\t\t")
+					}
+					fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", ur.Source, ur.Name, ur.Project, ur.Region, ur.Network, ur.PeerNetwork, ur.CIDR)
+				}
+			}
+			err := tw.Flush()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return validateReportOutputFormat(format)
+}
+
+func printUsages(w io.Writer, format string, usages []planner.RangeConfigUsage) error {
+	switch format {
+	case outputFormatJSON:
+		return printJSON(w, usages)
+
+	case outputFormatYAML:
+		return printYAML(w, usages)
+
+	case outputFormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TYPE\tRANGE TYPE\tNETWORK\tSUBNET MASK\tTOTAL\tUSED\tFREE\tFRAGMENTED\tUSED %\tTOP PROJECTS")
+		for _, u := range usages {
+			projects := []string{}
+			for _, p := range u.Projects {
+				projects = append(projects, fmt.Sprintf("%v (%v)", p.Project, p.UsedSlots))
+			}
+			fmt.Fprintf(tw, "%v\t%v\t%v\t/%v\t%v\t%v\t%v\t%v\t%.1f\t%v\n", u.RangeConfig.Type, u.RangeConfig.RangeType, u.RangeConfig.NetworkCIDR, u.RangeConfig.SubnetMask, u.TotalSlots, u.UsedSlots, u.FreeSlots, u.FragmentedSlots, u.PercentageUsed, strings.Join(projects, ", "))
+		}
+		return tw.Flush()
+	}
+
+	return validateReportOutputFormat(format)
+}
+
+func printOverlaps(w io.Writer, format string, overlaps []planner.RangeOverlap) error {
+	switch format {
+	case outputFormatJSON:
+		return printJSON(w, overlaps)
+
+	case outputFormatYAML:
+		return printYAML(w, overlaps)
+
+	case outputFormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CIDR\tSOURCE\tNAME\tPROJECT\tNETWORK\tOVERLAPS CIDR\tSOURCE\tNAME\tPROJECT\tNETWORK")
+		for _, o := range overlaps {
+			fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", o.Range.CIDR, o.Range.Source, o.Range.Name, o.Range.Project, o.Range.Network, o.OverlappingRange.CIDR, o.OverlappingRange.Source, o.OverlappingRange.Name, o.OverlappingRange.Project, o.OverlappingRange.Network)
+		}
+		return tw.Flush()
+	}
+
+	return validateReportOutputFormat(format)
+}
+
+func getEnvVarName(suggestion networkv1.Suggestion) string {
+	return envVarInvalidCharsRegex.ReplaceAllString(strings.ToUpper(string(suggestion.Type)), "_") + "_CIDR"
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/estafette/estafette-gcp-network-planner/services/planner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	organizationID     string
+	folderIDs          []string
+	includedProjectIDs []string
+	excludedProjectIDs []string
+)
+
+// addProjectFilterFlags adds the flags for selecting the projects to retrieve existing network ranges for
+func addProjectFilterFlags(c *cobra.Command) {
+	c.Flags().StringVar(&filter, "filter", "", "Filter for limiting projects to retrieve existing network ranges for, see https://cloud.google.com/resource-manager/reference/rest/v1/projects/list#query-parameters")
+	c.Flags().StringVar(&organizationID, "organization", "", "Organization id to limit projects to, including the projects in all of its folders")
+	c.Flags().StringSliceVar(&folderIDs, "folder", []string{}, "Folder ids to limit projects to, including the projects in their sub-folders")
+	c.Flags().StringSliceVar(&includedProjectIDs, "project", []string{}, "Project ids to include in addition to the projects matching --filter, --organization and --folder; when used on its own only these projects are included")
+	c.Flags().StringSliceVar(&excludedProjectIDs, "exclude-project", []string{}, "Project ids to leave out")
+}
+
+func getProjectFilter() planner.ProjectFilter {
+	return planner.ProjectFilter{
+		Filter:             filter,
+		OrganizationID:     organizationID,
+		FolderIDs:          folderIDs,
+		ProjectIDs:         includedProjectIDs,
+		ExcludedProjectIDs: excludedProjectIDs,
+	}
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	foundation "github.com/estafette/estafette-foundation"
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
+	"github.com/estafette/estafette-gcp-network-planner/services/planner"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reserveCIDRs   []string
+	reserveOwner   string
+	reserveComment string
+	reserveTTL     time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(reserveCmd)
+	rootCmd.AddCommand(releaseCmd)
+	rootCmd.AddCommand(reservationsCmd)
+	reservationsCmd.AddCommand(reservationsListCmd)
+
+	// command-specific flags
+	addProjectFilterFlags(reserveCmd)
+	addSelectorFlags(reserveCmd)
+	reserveCmd.Flags().StringVar(&sizeValue, "size", "", "Size of the suggested ranges, either a prefix length like /20 or counts like nodes=500,max-pods-per-node=110,services=1000; defaults to subnet_mask of the range config")
+	reserveCmd.Flags().StringVar(&allocationStrategyValue, "allocation-strategy", "", "Allocation strategy for picking free ranges: first-fit, best-fit or last-fit; overrides the allocation_strategy of the range configs, which defaults to first-fit")
+	reserveCmd.Flags().IntVar(&count, "count", 1, "Number of non-conflicting ranges to suggest and reserve per network type")
+	reserveCmd.Flags().StringSliceVar(&reserveCIDRs, "cidr", []string{}, "Reserve these exact ranges instead of suggesting them")
+	reserveCmd.Flags().StringSliceVar(&networkTypes, "type", []string{}, "Network types to suggest and reserve ranges for, comma-separated or repeated like --type node --type pod; defaults to all types declared in the config; with --cidr the single network type to record for the ranges")
+	reserveCmd.Flags().StringVar(&reserveOwner, "owner", os.Getenv("USER"), "Owner of the reservations")
+	reserveCmd.Flags().StringVar(&reserveComment, "comment", "", "Comment to describe what the reservations are for")
+	reserveCmd.Flags().DurationVar(&reserveTTL, "ttl", 0, "Time after which the reservations expire; when 0 they're kept until released")
+	reserveCmd.Flags().StringVarP(&outputFormat, "output", "o", outputFormatTable, "Output format for the reserved ranges: json, yaml, table or env")
+
+	reservationsListCmd.Flags().StringVarP(&outputFormat, "output", "o", outputFormatTable, "Output format for the reservations: json, yaml, table or env")
+}
+
+var reserveCmd = &cobra.Command{
+	Use:   "reserve",
+	Short: "Suggest free network ranges and reserve them, or reserve specific ranges with --cidr",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		// fail early on unsupported output format
+		err := validateOutputFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+
+		allocationStrategy, err := networkv1.ParseAllocationStrategy(allocationStrategyValue)
+		if err != nil {
+			return err
+		}
+
+		types, err := parseNetworkTypes(networkTypes)
+		if err != nil {
+			return err
+		}
+		if len(reserveCIDRs) > 0 && len(types) > 1 {
+			return fmt.Errorf("Only one --type can be set when reserving ranges with --cidr, got %v", len(types))
+		}
+
+		reservationStore, err := newReservationStore(cmd.Context(), true)
+		if err != nil {
+			return err
+		}
+
+		reservedAt := time.Now().UTC()
+		var expiresAt *time.Time
+		if reserveTTL > 0 {
+			t := reservedAt.Add(reserveTTL)
+			expiresAt = &t
+		}
+
+		// reserve exact ranges without talking to gcp
+		if len(reserveCIDRs) > 0 {
+			reserveType := networkv1.TypeUnknown
+			if len(types) > 0 {
+				reserveType = types[0]
+			}
+
+			plannerService, err := planner.NewService(cmd.Context(), nil, reservationStore, configFilePath)
+			if err != nil {
+				return err
+			}
+
+			reservations := []networkv1.Reservation{}
+			for _, c := range reserveCIDRs {
+				reservations = append(reservations, networkv1.Reservation{
+					CIDR:       c,
+					Type:       reserveType,
+					Owner:      reserveOwner,
+					Comment:    reserveComment,
+					ReservedAt: reservedAt,
+					ExpiresAt:  expiresAt,
+				})
+			}
+
+			// retry when the reservations have been modified concurrently
+			err = foundation.Retry(func() error {
+				return plannerService.Reserve(cmd.Context(), reservations...)
+			}, getReserveRetryOptions(planner.ErrReservationConflict)...)
+			if err != nil {
+				return err
+			}
+
+			return printReservations(cmd.OutOrStdout(), outputFormat, reservations)
+		}
+
+		// init gcp client
+		gcpClient, err := gcp.NewClient(cmd.Context(), concurrency)
+		if err != nil {
+			return err
+		}
+
+		// init planner service
+		plannerService, err := planner.NewService(cmd.Context(), gcpClient, reservationStore, configFilePath, planner.WithAllocationStrategy(allocationStrategy))
+		if err != nil {
+			return err
+		}
+
+		selector := getSelector()
+
+		size, err := networkv1.ParseSubnetSize(sizeValue)
+		if err != nil {
+			return err
+		}
+
+		// suggest again when someone else modified or took the suggested ranges in the meantime
+		var suggestions []networkv1.Suggestion
+		err = foundation.Retry(func() error {
+			suggestions, err = plannerService.Suggest(cmd.Context(), getProjectFilter(), selector, count, size, types...)
+			if err != nil {
+				return err
+			}
+
+			reservations := []networkv1.Reservation{}
+			for _, s := range suggestions {
+				reservations = append(reservations, networkv1.Reservation{
+					CIDR:       s.CIDR,
+					Type:       s.Type,
+					Owner:      reserveOwner,
+					Comment:    reserveComment,
+					ReservedAt: reservedAt,
+					ExpiresAt:  expiresAt,
+				})
+			}
+
+			return plannerService.Reserve(cmd.Context(), reservations...)
+		}, getReserveRetryOptions(planner.ErrReservationConflict, planner.ErrRangeAlreadyReserved)...)
+		if err != nil {
+			return err
+		}
+
+		return printSuggestions(cmd.OutOrStdout(), outputFormat, suggestions)
+	},
+}
+
+var releaseCmd = &cobra.Command{
+	Use:   "release [cidr...]",
+	Short: "Release reserved network ranges",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		reservationStore, err := newReservationStore(cmd.Context(), true)
+		if err != nil {
+			return err
+		}
+
+		plannerService, err := planner.NewService(cmd.Context(), nil, reservationStore, configFilePath)
+		if err != nil {
+			return err
+		}
+
+		return plannerService.Release(cmd.Context(), args...)
+	},
+}
+
+var reservationsCmd = &cobra.Command{
+	Use:   "reservations",
+	Short: "Manage reserved network ranges",
+}
+
+var reservationsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List active reservations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		// fail early on unsupported output format
+		err := validateOutputFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+
+		reservationStore, err := newReservationStore(cmd.Context(), true)
+		if err != nil {
+			return err
+		}
+
+		plannerService, err := planner.NewService(cmd.Context(), nil, reservationStore, configFilePath)
+		if err != nil {
+			return err
+		}
+
+		reservations, err := plannerService.ListReservations(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		return printReservations(cmd.OutOrStdout(), outputFormat, reservations)
+	},
+}
+
+func getReserveRetryOptions(retryableErrors ...error) []foundation.RetryOption {
+	return []foundation.RetryOption{
+		func(c *foundation.RetryConfig) {
+			c.IsRetryableError = func(err error) bool {
+				for _, re := range retryableErrors {
+					if errors.Is(err, re) {
+						log.Info().Msgf("Retrying after error: %v", err)
+						return true
+					}
+				}
+				return false
+			}
+		},
+		foundation.LastErrorOnly(true),
+		foundation.Attempts(5),
+		foundation.DelayMillisecond(500),
+		foundation.ExponentialJitterBackoff(),
+	}
+}
+
+func newReservationStore(ctx context.Context, required bool) (planner.ReservationStore, error) {
+	if reservationsLocation == "" {
+		if required {
+			return nil, fmt.Errorf("Flag --reservations is required for this command")
+		}
+		return nil, nil
+	}
+
+	return planner.NewReservationStore(ctx, reservationsLocation, storageEndpoint)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	stdlog "log"
 	"os"
 	"runtime"
 
@@ -24,9 +25,11 @@ var (
 
 // rootCmd represents the base command when called without any subcommands
 var (
-	verbose        bool
-	concurrency    int
-	configFilePath string
+	verbose              bool
+	concurrency          int
+	configFilePath       string
+	reservationsLocation string
+	storageEndpoint      string
 
 	rootCmd = &cobra.Command{
 		Use:   "gcp-network-planner",
@@ -38,6 +41,8 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().IntVar(&concurrency, "concurrency", 5, "level of concurrency")
 	rootCmd.PersistentFlags().StringVar(&configFilePath, "config-file", "", "path to config file")
+	rootCmd.PersistentFlags().StringVar(&reservationsLocation, "reservations", "", "path to reservations file or gs://bucket/object location; when empty reservations are not taken into account")
+	rootCmd.PersistentFlags().StringVar(&storageEndpoint, "storage-endpoint", "", "endpoint of a gcs compatible server to store reservations in, for example http://localhost:4443/storage/v1/")
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -46,6 +51,9 @@ func Execute() {
 
 	foundation.InitLoggingByFormatSilent(foundation.NewApplicationInfo(appgroup, app, version, branch, revision, buildDate), foundation.LogFormatConsole)
 
+	// write logs to stderr so stdout only contains command output
+	initLoggingToStderr()
+
 	// create context to cancel commands on sigterm
 	ctx := foundation.InitCancellationContext(context.Background())
 
@@ -57,7 +65,29 @@ func Execute() {
 	}
 
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+func initLoggingToStderr() {
+	output := zerolog.ConsoleWriter{
+		Out:     os.Stderr,
+		NoColor: false,
+	}
+	output.FormatTimestamp = func(i interface{}) string {
+		return ""
+	}
+	output.FormatCaller = func(i interface{}) string {
+		return ""
+	}
+	output.FormatLevel = func(i interface{}) string {
+		return ""
+	}
+
+	log.Logger = log.Output(output)
+
+	// foundation points the standard logger at stdout as well, so output of dependencies doesn't end up in the command output
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(log.Logger)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	stdlog "log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	foundation "github.com/estafette/estafette-foundation"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitLoggingToStderr(t *testing.T) {
+
+	t.Run("KeepsLogsOutOfJSONOutput", func(t *testing.T) {
+
+		dir, err := ioutil.TempDir("", "gcp-network-planner")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+
+		stdout := os.Stdout
+		defer func() { os.Stdout = stdout }()
+		reader, writer, err := os.Pipe()
+		assert.Nil(t, err)
+		os.Stdout = writer
+
+		// initialize logging like Execute does, which first points all loggers at stdout
+		foundation.InitLoggingByFormatSilent(foundation.NewApplicationInfo(appgroup, app, version, branch, revision, buildDate), foundation.LogFormatConsole)
+		initLoggingToStderr()
+
+		rootCmd.SetArgs([]string{"reserve", "--cidr", "10.0.0.0/16", "--type", "node", "--reservations", filepath.Join(dir, "reservations.json"), "-o", "json"})
+
+		// act
+		log.Info().Msg("Log message of the planner")
+		stdlog.Print("Log message of a dependency")
+		err = rootCmd.ExecuteContext(context.Background())
+
+		writer.Close()
+		output, readErr := ioutil.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.Nil(t, readErr)
+		var reservations []map[string]interface{}
+		assert.Nil(t, json.Unmarshal(output, &reservations), string(output))
+		assert.Equal(t, 1, len(reservations))
+	})
+}
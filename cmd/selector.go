@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/spf13/cobra"
+)
+
+var (
+	region      string
+	environment string
+	networkName string
+)
+
+// addSelectorFlags adds the flags for selecting the range configs to suggest ranges from
+func addSelectorFlags(c *cobra.Command) {
+	c.Flags().StringVar(&region, "region", "", "Region to select range configs for; range configs without region apply to all regions")
+	c.Flags().StringVar(&environment, "environment", "", "Environment to select range configs for; range configs without environment apply to all environments")
+	c.Flags().StringVar(&networkName, "network", "", "Network name to select range configs for; range configs without network_name apply to all networks")
+}
+
+func getSelector() networkv1.Selector {
+	return networkv1.Selector{
+		Region:      region,
+		Environment: environment,
+		NetworkName: networkName,
+	}
+}
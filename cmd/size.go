@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
+	"github.com/estafette/estafette-gcp-network-planner/services/planner"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodes          int
+	maxPodsPerNode int
+	services       int
+)
+
+func init() {
+	rootCmd.AddCommand(sizeCmd)
+
+	// command-specific flags
+	sizeCmd.Flags().IntVar(&nodes, "nodes", 0, "Maximum number of nodes of the cluster")
+	sizeCmd.Flags().IntVar(&maxPodsPerNode, "max-pods-per-node", networkv1.DefaultMaxPodsPerNode, "Maximum number of pods per node of the cluster")
+	sizeCmd.Flags().IntVar(&services, "services", 0, "Maximum number of services of the cluster")
+	addProjectFilterFlags(sizeCmd)
+	addSelectorFlags(sizeCmd)
+	sizeCmd.Flags().StringVar(&allocationStrategyValue, "allocation-strategy", "", "Allocation strategy for picking free ranges: first-fit, best-fit or last-fit; overrides the allocation_strategy of the range configs, which defaults to first-fit")
+	sizeCmd.Flags().IntVar(&count, "count", 1, "Number of non-conflicting ranges to suggest for each network type")
+	sizeCmd.Flags().StringVarP(&outputFormat, "output", "o", outputFormatTable, "Output format for the suggestions: json, yaml, table or env")
+
+	_ = sizeCmd.MarkFlagRequired("nodes")
+	_ = sizeCmd.MarkFlagRequired("services")
+}
+
+var sizeCmd = &cobra.Command{
+	Use:   "size",
+	Short: "Calculate the node, pod and service ranges a gke cluster needs and suggest free ranges of those sizes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		// fail early on unsupported output format or cluster size
+		err := validateOutputFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+
+		allocationStrategy, err := networkv1.ParseAllocationStrategy(allocationStrategyValue)
+		if err != nil {
+			return err
+		}
+
+		clusterSize, err := networkv1.GetClusterSize(nodes, maxPodsPerNode, services)
+		if err != nil {
+			return err
+		}
+
+		log.Info().Msgf("A cluster with %v nodes, %v pods per node and %v services needs a /%v node range, a /%v pod range and a /%v service range", nodes, maxPodsPerNode, services, clusterSize.NodePrefixLength, clusterSize.PodPrefixLength, clusterSize.ServicePrefixLength)
+
+		// init gcp client
+		gcpClient, err := gcp.NewClient(cmd.Context(), concurrency)
+		if err != nil {
+			return err
+		}
+
+		// init reservation store
+		reservationStore, err := newReservationStore(cmd.Context(), false)
+		if err != nil {
+			return err
+		}
+
+		// init planner service
+		plannerService, err := planner.NewService(cmd.Context(), gcpClient, reservationStore, configFilePath, planner.WithAllocationStrategy(allocationStrategy))
+		if err != nil {
+			return err
+		}
+
+		selector := getSelector()
+
+		size := networkv1.SubnetSize{
+			Nodes:          nodes,
+			MaxPodsPerNode: maxPodsPerNode,
+			Services:       services,
+		}
+
+		suggestions, err := plannerService.Suggest(cmd.Context(), getProjectFilter(), selector, count, size, networkv1.TypeNode, networkv1.TypePod, networkv1.TypeService)
+		if err != nil {
+			return err
+		}
+
+		return printSuggestions(cmd.OutOrStdout(), outputFormat, suggestions)
+	},
+}
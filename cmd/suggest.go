@@ -1,20 +1,32 @@
 package cmd
 
 import (
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
 	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
 	"github.com/estafette/estafette-gcp-network-planner/services/planner"
 	"github.com/spf13/cobra"
 )
 
 var (
-	filter string
+	filter                  string
+	outputFormat            string
+	count                   int
+	networkTypes            []string
+	sizeValue               string
+	allocationStrategyValue string
 )
 
 func init() {
 	rootCmd.AddCommand(suggestCmd)
 
 	// command-specific flags
-	suggestCmd.Flags().StringVar(&filter, "filter", "", "Filter for limiting projects to retrieve existing network ranges for, see https://cloud.google.com/resource-manager/reference/rest/v1/projects/list#query-parameters")
+	addProjectFilterFlags(suggestCmd)
+	addSelectorFlags(suggestCmd)
+	suggestCmd.Flags().StringSliceVar(&networkTypes, "type", []string{}, "Network types to suggest ranges for, comma-separated or repeated like --type node --type pod; defaults to all types declared in the config")
+	suggestCmd.Flags().StringVar(&sizeValue, "size", "", "Size of the suggested ranges, either a prefix length like /20 or counts like nodes=500,max-pods-per-node=110,services=1000; needs to be within min_subnet_mask and max_subnet_mask of the range config, defaults to subnet_mask")
+	suggestCmd.Flags().StringVar(&allocationStrategyValue, "allocation-strategy", "", "Allocation strategy for picking free ranges: first-fit, best-fit or last-fit; overrides the allocation_strategy of the range configs, which defaults to first-fit")
+	suggestCmd.Flags().IntVar(&count, "count", 1, "Number of non-conflicting ranges to suggest for each network type")
+	suggestCmd.Flags().StringVarP(&outputFormat, "output", "o", outputFormatTable, "Output format for the suggestions: json, yaml, table or env")
 }
 
 var suggestCmd = &cobra.Command{
@@ -22,23 +34,64 @@ var suggestCmd = &cobra.Command{
 	Short: "Suggest a free network range for a subnetwork",
 	RunE: func(cmd *cobra.Command, args []string) error {
 
+		// fail early on unsupported output format
+		err := validateOutputFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+
+		allocationStrategy, err := networkv1.ParseAllocationStrategy(allocationStrategyValue)
+		if err != nil {
+			return err
+		}
+
 		// init gcp client
 		gcpClient, err := gcp.NewClient(cmd.Context(), concurrency)
 		if err != nil {
 			return err
 		}
 
+		// init reservation store
+		reservationStore, err := newReservationStore(cmd.Context(), false)
+		if err != nil {
+			return err
+		}
+
 		// init planner service
-		plannerService, err := planner.NewService(cmd.Context(), gcpClient, configFilePath)
+		plannerService, err := planner.NewService(cmd.Context(), gcpClient, reservationStore, configFilePath, planner.WithAllocationStrategy(allocationStrategy))
+		if err != nil {
+			return err
+		}
+
+		selector := getSelector()
+
+		types, err := parseNetworkTypes(networkTypes)
 		if err != nil {
 			return err
 		}
 
-		_, err = plannerService.Suggest(cmd.Context(), filter)
+		size, err := networkv1.ParseSubnetSize(sizeValue)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		suggestions, err := plannerService.Suggest(cmd.Context(), getProjectFilter(), selector, count, size, types...)
+		if err != nil {
+			return err
+		}
+
+		return printSuggestions(cmd.OutOrStdout(), outputFormat, suggestions)
 	},
 }
+
+func parseNetworkTypes(values []string) (types []networkv1.Type, err error) {
+	for _, v := range values {
+		t, err := networkv1.ParseType(v)
+		if err != nil {
+			return types, err
+		}
+		types = append(types, t)
+	}
+
+	return
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
+	"github.com/estafette/estafette-gcp-network-planner/services/planner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topProjects int
+)
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+
+	// command-specific flags
+	addProjectFilterFlags(usageCmd)
+	usageCmd.Flags().IntVar(&topProjects, "top", 5, "Number of projects using the most slots to list per range config; when 0 all projects are listed")
+	usageCmd.Flags().StringVarP(&outputFormat, "output", "o", outputFormatTable, "Output format for the usage report: json, yaml or table")
+}
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Report how many subnet slots of each configured range are used, free and fragmented",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		// fail early on unsupported output format
+		err := validateReportOutputFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+
+		// init gcp client
+		gcpClient, err := gcp.NewClient(cmd.Context(), concurrency)
+		if err != nil {
+			return err
+		}
+
+		// init reservation store
+		reservationStore, err := newReservationStore(cmd.Context(), false)
+		if err != nil {
+			return err
+		}
+
+		// init planner service
+		plannerService, err := planner.NewService(cmd.Context(), gcpClient, reservationStore, configFilePath)
+		if err != nil {
+			return err
+		}
+
+		usages, err := plannerService.Usage(cmd.Context(), getProjectFilter())
+		if err != nil {
+			return err
+		}
+
+		// limit to the projects using the most slots
+		if topProjects > 0 {
+			for i := range usages {
+				if len(usages[i].Projects) > topProjects {
+					usages[i].Projects = usages[i].Projects[:topProjects]
+				}
+			}
+		}
+
+		return printUsages(cmd.OutOrStdout(), outputFormat, usages)
+	},
+}
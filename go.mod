@@ -11,4 +11,5 @@ require (
 	github.com/stretchr/testify v1.4.0
 	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 	google.golang.org/api v0.31.0
+	gopkg.in/yaml.v2 v2.2.2
 )
@@ -0,0 +1,422 @@
+package planner
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+)
+
+// ipInterval is an inclusive range of addresses, with the used ranges that occupy it
+type ipInterval struct {
+	first      *big.Int
+	last       *big.Int
+	usedRanges []UsedRange
+}
+
+// usedRangeIndex holds used ranges sorted and merged, so the first used range overlapping a candidate can be found with a binary search
+type usedRangeIndex struct {
+	intervals []ipInterval
+}
+
+// newUsedRangeIndex parses the used ranges once
+func newUsedRangeIndex(usedRanges []UsedRange) (index *usedRangeIndex, err error) {
+
+	intervals := make([]ipInterval, 0, len(usedRanges))
+	for _, ur := range usedRanges {
+		_, ipnet, parseErr := net.ParseCIDR(ur.CIDR)
+		if parseErr != nil {
+			return nil, fmt.Errorf("Parsing %v failed", ur.CIDR)
+		}
+		first, last := ipNetToInterval(ipnet)
+		intervals = append(intervals, ipInterval{first: first, last: last, usedRanges: []UsedRange{ur}})
+	}
+
+	sort.SliceStable(intervals, func(i, j int) bool {
+		return intervals[i].first.Cmp(intervals[j].first) < 0
+	})
+
+	// merge overlapping and adjacent intervals
+	merged := make([]ipInterval, 0, len(intervals))
+	for _, iv := range intervals {
+		if len(merged) > 0 {
+			prev := &merged[len(merged)-1]
+			if new(big.Int).Add(prev.last, big.NewInt(1)).Cmp(iv.first) >= 0 {
+				if iv.last.Cmp(prev.last) > 0 {
+					prev.last = iv.last
+				}
+				prev.usedRanges = append(prev.usedRanges, iv.usedRanges...)
+				continue
+			}
+		}
+		merged = append(merged, iv)
+	}
+
+	return &usedRangeIndex{intervals: merged}, nil
+}
+
+// firstOverlap returns the first used interval overlapping with the inclusive range first-last
+func (idx *usedRangeIndex) firstOverlap(first, last *big.Int) (interval ipInterval, overlaps bool) {
+	i := sort.Search(len(idx.intervals), func(i int) bool {
+		return idx.intervals[i].last.Cmp(first) >= 0
+	})
+	if i < len(idx.intervals) && idx.intervals[i].first.Cmp(last) <= 0 {
+		return idx.intervals[i], true
+	}
+
+	return interval, false
+}
+
+// firstFreeSubnet returns the lowest subnet with prefix length subnetMask within network not overlapping any used range; it skips past used ranges instead of checking every candidate
+func (idx *usedRangeIndex) firstFreeSubnet(network *net.IPNet, subnetMask int, onBlocked func(candidate *net.IPNet, interval ipInterval)) (subnet *net.IPNet, position *big.Int) {
+
+	ones, bits := network.Mask.Size()
+	if subnetMask < ones || subnetMask > bits {
+		return nil, nil
+	}
+
+	base, networkLast := ipNetToInterval(network)
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-subnetMask))
+	one := big.NewInt(1)
+
+	candidateFirst := new(big.Int).Set(base)
+	for {
+		candidateLast := new(big.Int).Add(candidateFirst, size)
+		candidateLast.Sub(candidateLast, one)
+		if candidateLast.Cmp(networkLast) > 0 {
+			return nil, nil
+		}
+
+		interval, overlaps := idx.firstOverlap(candidateFirst, candidateLast)
+		if !overlaps {
+			position = new(big.Int).Sub(candidateFirst, base)
+			position.Div(position, size)
+			return intervalToIPNet(candidateFirst, subnetMask, bits), position
+		}
+
+		if onBlocked != nil {
+			onBlocked(intervalToIPNet(candidateFirst, subnetMask, bits), interval)
+		}
+
+		// jump to the first aligned candidate after the used interval
+		offset := new(big.Int).Sub(interval.last, base)
+		offset.Add(offset, one)
+		offset.Add(offset, size)
+		offset.Sub(offset, one)
+		offset.Div(offset, size)
+		offset.Mul(offset, size)
+		candidateFirst = offset.Add(offset, base)
+	}
+}
+
+// lastOverlap returns the last used interval overlapping with the inclusive range first-last
+func (idx *usedRangeIndex) lastOverlap(first, last *big.Int) (interval ipInterval, overlaps bool) {
+	i := sort.Search(len(idx.intervals), func(i int) bool {
+		return idx.intervals[i].first.Cmp(last) > 0
+	})
+	if i > 0 && idx.intervals[i-1].last.Cmp(first) >= 0 {
+		return idx.intervals[i-1], true
+	}
+
+	return interval, false
+}
+
+// lastFreeSubnet returns the highest subnet with prefix length subnetMask within network not overlapping any used range; it skips past used ranges top-down instead of checking every candidate
+func (idx *usedRangeIndex) lastFreeSubnet(network *net.IPNet, subnetMask int, onBlocked func(candidate *net.IPNet, interval ipInterval)) (subnet *net.IPNet, position *big.Int) {
+
+	ones, bits := network.Mask.Size()
+	if subnetMask < ones || subnetMask > bits {
+		return nil, nil
+	}
+
+	base, networkLast := ipNetToInterval(network)
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-subnetMask))
+	one := big.NewInt(1)
+
+	candidateFirst := new(big.Int).Sub(networkLast, size)
+	candidateFirst.Add(candidateFirst, one)
+	for {
+		if candidateFirst.Cmp(base) < 0 {
+			return nil, nil
+		}
+		candidateLast := new(big.Int).Add(candidateFirst, size)
+		candidateLast.Sub(candidateLast, one)
+
+		interval, overlaps := idx.lastOverlap(candidateFirst, candidateLast)
+		if !overlaps {
+			position = new(big.Int).Sub(candidateFirst, base)
+			position.Div(position, size)
+			return intervalToIPNet(candidateFirst, subnetMask, bits), position
+		}
+
+		if onBlocked != nil {
+			onBlocked(intervalToIPNet(candidateFirst, subnetMask, bits), interval)
+		}
+
+		// jump to the last aligned candidate before the used interval
+		if interval.first.Cmp(base) <= 0 {
+			return nil, nil
+		}
+		offset := new(big.Int).Sub(interval.first, base)
+		offset.Div(offset, size)
+		offset.Sub(offset, one)
+		offset.Mul(offset, size)
+		candidateFirst = offset.Add(offset, base)
+	}
+}
+
+// bestFreeSubnet returns the lowest subnet with prefix length subnetMask in the smallest block of free addresses within network it fits in, so larger free blocks are kept for larger subnets
+func (idx *usedRangeIndex) bestFreeSubnet(network *net.IPNet, subnetMask int, onBlocked func(candidate *net.IPNet, interval ipInterval)) (subnet *net.IPNet, position *big.Int) {
+
+	ones, bits := network.Mask.Size()
+	if subnetMask < ones || subnetMask > bits {
+		return nil, nil
+	}
+
+	base, networkLast := ipNetToInterval(network)
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-subnetMask))
+	one := big.NewInt(1)
+
+	var bestCandidate, bestBlockSize *big.Int
+	considerBlock := func(blockFirst, blockLast *big.Int) {
+		if blockFirst.Cmp(blockLast) > 0 {
+			return
+		}
+
+		// first aligned candidate within the free block
+		candidate := new(big.Int).Sub(blockFirst, base)
+		candidate.Add(candidate, size)
+		candidate.Sub(candidate, one)
+		candidate.Div(candidate, size)
+		candidate.Mul(candidate, size)
+		candidate.Add(candidate, base)
+
+		candidateLast := new(big.Int).Add(candidate, size)
+		candidateLast.Sub(candidateLast, one)
+		if candidateLast.Cmp(blockLast) > 0 {
+			return
+		}
+
+		blockSize := new(big.Int).Sub(blockLast, blockFirst)
+		if bestBlockSize == nil || blockSize.Cmp(bestBlockSize) < 0 {
+			bestCandidate = candidate
+			bestBlockSize = blockSize
+		}
+	}
+
+	// the free blocks are the gaps between the merged used intervals
+	blockFirst := new(big.Int).Set(base)
+	var lastBlocked *big.Int
+	for _, iv := range idx.intervals {
+		if iv.last.Cmp(base) < 0 {
+			continue
+		}
+		if iv.first.Cmp(networkLast) > 0 {
+			break
+		}
+		considerBlock(blockFirst, new(big.Int).Sub(iv.first, one))
+		blockFirst = new(big.Int).Add(iv.last, one)
+
+		// report the aligned candidate the used interval starts in, once per candidate like first-fit does
+		if onBlocked != nil {
+			blocked := iv.first
+			if blocked.Cmp(base) < 0 {
+				blocked = base
+			}
+			blocked = new(big.Int).Sub(blocked, base)
+			blocked.Div(blocked, size)
+			blocked.Mul(blocked, size)
+			blocked.Add(blocked, base)
+			if lastBlocked == nil || blocked.Cmp(lastBlocked) > 0 {
+				onBlocked(intervalToIPNet(blocked, subnetMask, bits), iv)
+				lastBlocked = blocked
+			}
+		}
+	}
+	considerBlock(blockFirst, networkLast)
+
+	if bestCandidate == nil {
+		return nil, nil
+	}
+
+	position = new(big.Int).Sub(bestCandidate, base)
+	position.Div(position, size)
+
+	return intervalToIPNet(bestCandidate, subnetMask, bits), position
+}
+
+// allocator picks one of the free subnets of a network, so allocation strategies can be added without touching the code suggesting ranges
+type allocator interface {
+	freeSubnet(idx *usedRangeIndex, network *net.IPNet, subnetMask int, onBlocked func(candidate *net.IPNet, interval ipInterval)) (subnet *net.IPNet, position *big.Int)
+}
+
+type firstFitAllocator struct{}
+
+func (a firstFitAllocator) freeSubnet(idx *usedRangeIndex, network *net.IPNet, subnetMask int, onBlocked func(candidate *net.IPNet, interval ipInterval)) (subnet *net.IPNet, position *big.Int) {
+	return idx.firstFreeSubnet(network, subnetMask, onBlocked)
+}
+
+type bestFitAllocator struct{}
+
+func (a bestFitAllocator) freeSubnet(idx *usedRangeIndex, network *net.IPNet, subnetMask int, onBlocked func(candidate *net.IPNet, interval ipInterval)) (subnet *net.IPNet, position *big.Int) {
+	return idx.bestFreeSubnet(network, subnetMask, onBlocked)
+}
+
+type lastFitAllocator struct{}
+
+func (a lastFitAllocator) freeSubnet(idx *usedRangeIndex, network *net.IPNet, subnetMask int, onBlocked func(candidate *net.IPNet, interval ipInterval)) (subnet *net.IPNet, position *big.Int) {
+	return idx.lastFreeSubnet(network, subnetMask, onBlocked)
+}
+
+var (
+	allocators = map[networkv1.AllocationStrategy]allocator{
+		networkv1.AllocationStrategyFirstFit: firstFitAllocator{},
+		networkv1.AllocationStrategyBestFit:  bestFitAllocator{},
+		networkv1.AllocationStrategyLastFit:  lastFitAllocator{},
+	}
+)
+
+// getAllocator returns the allocator implementing the allocation strategy
+func getAllocator(strategy networkv1.AllocationStrategy) (allocator, error) {
+	if a, ok := allocators[strategy]; ok {
+		return a, nil
+	}
+
+	return nil, fmt.Errorf("Allocation strategy %v is not supported", strategy)
+}
+
+// blockedCandidates returns the candidate subnets with prefix length subnetMask within network that are blocked by used ranges, grouped per used interval, together with the total and free number of candidates
+func (idx *usedRangeIndex) blockedCandidates(network *net.IPNet, subnetMask int) (blocked []BlockedCandidates, total, free *big.Int) {
+
+	blocked = []BlockedCandidates{}
+
+	ones, bits := network.Mask.Size()
+	if subnetMask < ones || subnetMask > bits {
+		return blocked, big.NewInt(0), big.NewInt(0)
+	}
+
+	base, networkLast := ipNetToInterval(network)
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-subnetMask))
+	one := big.NewInt(1)
+
+	total = new(big.Int).Lsh(big.NewInt(1), uint(subnetMask-ones))
+	blockedTotal := big.NewInt(0)
+
+	// keep track of the last blocked candidate, since a candidate can be blocked by more than one used interval
+	var lastBlocked *big.Int
+	for _, iv := range idx.intervals {
+		if iv.last.Cmp(base) < 0 || iv.first.Cmp(networkLast) > 0 {
+			continue
+		}
+
+		first := iv.first
+		if first.Cmp(base) < 0 {
+			first = base
+		}
+		last := iv.last
+		if last.Cmp(networkLast) > 0 {
+			last = networkLast
+		}
+
+		firstCandidate := new(big.Int).Sub(first, base)
+		firstCandidate.Div(firstCandidate, size)
+		lastCandidate := new(big.Int).Sub(last, base)
+		lastCandidate.Div(lastCandidate, size)
+
+		count := new(big.Int).Sub(lastCandidate, firstCandidate)
+		count.Add(count, one)
+
+		blocked = append(blocked, BlockedCandidates{
+			FirstCIDR: intervalToIPNet(new(big.Int).Add(base, new(big.Int).Mul(firstCandidate, size)), subnetMask, bits).String(),
+			LastCIDR:  intervalToIPNet(new(big.Int).Add(base, new(big.Int).Mul(lastCandidate, size)), subnetMask, bits).String(),
+			Count:     count,
+			BlockedBy: iv.usedRanges,
+		})
+
+		if lastBlocked != nil && firstCandidate.Cmp(lastBlocked) <= 0 {
+			count = new(big.Int).Sub(count, one)
+		}
+		blockedTotal.Add(blockedTotal, count)
+		lastBlocked = lastCandidate
+	}
+
+	return blocked, total, new(big.Int).Sub(total, blockedTotal)
+}
+
+// slotUsage returns the total number of candidate subnets with prefix length subnetMask within network, how many of them overlap used ranges and how many of those are only partially used
+func (idx *usedRangeIndex) slotUsage(network *net.IPNet, subnetMask int) (total, used, fragmented *big.Int) {
+
+	_, total, free := idx.blockedCandidates(network, subnetMask)
+	used = new(big.Int).Sub(total, free)
+
+	ones, bits := network.Mask.Size()
+	if subnetMask < ones || subnetMask > bits {
+		return total, used, big.NewInt(0)
+	}
+
+	base, networkLast := ipNetToInterval(network)
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-subnetMask))
+	one := big.NewInt(1)
+
+	// count the candidates that are completely covered by a single used interval; since adjacent used ranges are merged no candidate is covered by more than one
+	fullyUsed := big.NewInt(0)
+	for _, iv := range idx.intervals {
+		if iv.last.Cmp(base) < 0 || iv.first.Cmp(networkLast) > 0 {
+			continue
+		}
+
+		first := iv.first
+		if first.Cmp(base) < 0 {
+			first = base
+		}
+		last := iv.last
+		if last.Cmp(networkLast) > 0 {
+			last = networkLast
+		}
+
+		// first candidate starting at or after the start of the interval
+		firstCandidate := new(big.Int).Sub(first, base)
+		firstCandidate.Add(firstCandidate, size)
+		firstCandidate.Sub(firstCandidate, one)
+		firstCandidate.Div(firstCandidate, size)
+
+		// first candidate ending after the end of the interval
+		endCandidate := new(big.Int).Sub(last, base)
+		endCandidate.Add(endCandidate, one)
+		endCandidate.Div(endCandidate, size)
+
+		if endCandidate.Cmp(firstCandidate) > 0 {
+			fullyUsed.Add(fullyUsed, endCandidate.Sub(endCandidate, firstCandidate))
+		}
+	}
+
+	return total, used, new(big.Int).Sub(used, fullyUsed)
+}
+
+func ipNetToInterval(ipnet *net.IPNet) (first, last *big.Int) {
+	ip := ipnet.IP
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	first = new(big.Int).SetBytes(ip.Mask(ipnet.Mask))
+
+	ones, bits := ipnet.Mask.Size()
+	last = new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	last.Sub(last, big.NewInt(1))
+	last.Add(last, first)
+
+	return
+}
+
+func intervalToIPNet(first *big.Int, prefixLength, bits int) *net.IPNet {
+	ip := make(net.IP, bits/8)
+	first.FillBytes(ip)
+
+	return &net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(prefixLength, bits),
+	}
+}
@@ -0,0 +1,278 @@
+package planner
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFirstFreeSubnet(t *testing.T) {
+
+	t.Run("ReturnsFirstSubnetIfNoRangesAreUsed", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{}, []string{}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("172.28.0.0/14")
+
+		// act
+		subnet, position := index.firstFreeSubnet(network, 21, nil)
+
+		assert.Equal(t, "172.28.0.0/21", subnet.String())
+		assert.Equal(t, int64(0), position.Int64())
+	})
+
+	t.Run("SkipsPastUsedRangeLargerThanSubnet", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"172.28.0.0/16"}, []string{"route"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("172.28.0.0/14")
+
+		blocked := 0
+
+		// act
+		subnet, position := index.firstFreeSubnet(network, 21, func(candidate *net.IPNet, interval ipInterval) { blocked++ })
+
+		assert.Equal(t, "172.29.0.0/21", subnet.String())
+		assert.Equal(t, int64(32), position.Int64())
+		assert.Equal(t, 1, blocked)
+	})
+
+	t.Run("SkipsToNextAlignedSubnetAfterUsedRangeSmallerThanSubnet", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"172.28.0.16/28", "172.28.8.0/24"}, []string{"subnet", "subnet"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("172.28.0.0/14")
+
+		// act
+		subnet, _ := index.firstFreeSubnet(network, 21, nil)
+
+		assert.Equal(t, "172.28.16.0/21", subnet.String())
+	})
+
+	t.Run("MergesOverlappingAndAdjacentUsedRanges", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"10.0.1.0/24", "10.0.0.0/24", "10.0.0.0/23", "10.0.2.0/24", "10.0.8.0/24"}, []string{"a", "b", "c", "d", "e"}))
+		assert.Nil(t, err)
+
+		assert.Equal(t, 2, len(index.intervals))
+		names := []string{}
+		for _, ur := range index.intervals[0].usedRanges {
+			names = append(names, ur.Name)
+		}
+		assert.Equal(t, []string{"b", "c", "a", "d"}, names)
+	})
+
+	t.Run("ReturnsNilIfAllSubnetsAreUsed", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"172.28.0.0/15", "172.30.0.0/15"}, []string{"a", "b"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("172.28.0.0/14")
+
+		// act
+		subnet, position := index.firstFreeSubnet(network, 15, nil)
+
+		assert.Nil(t, subnet)
+		assert.Nil(t, position)
+	})
+
+	t.Run("ReturnsFreeIPv6SubnetWithoutEnumeratingAllCandidates", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"fd12:3456:789a::/49"}, []string{"route"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("fd12:3456:789a::/48")
+
+		// act
+		subnet, _ := index.firstFreeSubnet(network, 112, nil)
+
+		assert.Equal(t, "fd12:3456:789a:8000::/112", subnet.String())
+	})
+
+	t.Run("ReturnsFirstGapInLargeSupernetWithManyUsedRanges", func(t *testing.T) {
+
+		// 10.0.0.0/8 carved into /28s has 1M candidates; use the first 20000 of them
+		cidrs := []string{}
+		sources := []string{}
+		for i := 0; i < 20000; i++ {
+			if i == 17000 {
+				continue
+			}
+			cidrs = append(cidrs, fmt.Sprintf("10.%v.%v.%v/28", (i*16)>>16, ((i*16)>>8)&255, (i*16)&255))
+			sources = append(sources, "subnet")
+		}
+		index, err := newUsedRangeIndex(newTestUsedRanges(cidrs, sources))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("10.0.0.0/8")
+
+		// act
+		subnet, position := index.firstFreeSubnet(network, 28, nil)
+
+		assert.Equal(t, "10.4.38.128/28", subnet.String())
+		assert.Equal(t, int64(17000), position.Int64())
+	})
+}
+
+func newTestUsedRanges(cidrs []string, names []string) (usedRanges []UsedRange) {
+	for i, c := range cidrs {
+		usedRanges = append(usedRanges, UsedRange{CIDR: c, Source: UsedRangeSourceSubnetwork, Name: names[i]})
+	}
+	return
+}
+
+func TestLastFreeSubnet(t *testing.T) {
+
+	t.Run("ReturnsLastSubnetIfNoRangesAreUsed", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{}, []string{}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("172.28.0.0/14")
+
+		// act
+		subnet, position := index.lastFreeSubnet(network, 21, nil)
+
+		assert.Equal(t, "172.31.248.0/21", subnet.String())
+		assert.Equal(t, int64(127), position.Int64())
+	})
+
+	t.Run("SkipsPastUsedRangeAtTopOfNetwork", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"172.31.0.0/16"}, []string{"route"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("172.28.0.0/14")
+
+		blocked := 0
+
+		// act
+		subnet, position := index.lastFreeSubnet(network, 21, func(candidate *net.IPNet, interval ipInterval) { blocked++ })
+
+		assert.Equal(t, "172.30.248.0/21", subnet.String())
+		assert.Equal(t, int64(95), position.Int64())
+		assert.Equal(t, 1, blocked)
+	})
+
+	t.Run("ReturnsNilIfAllSubnetsAreUsed", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"172.28.0.0/15", "172.30.0.0/16", "172.31.0.0/16"}, []string{"a", "b", "c"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("172.28.0.0/14")
+
+		// act
+		subnet, position := index.lastFreeSubnet(network, 16, nil)
+
+		assert.Nil(t, subnet)
+		assert.Nil(t, position)
+	})
+}
+
+func TestBestFreeSubnet(t *testing.T) {
+
+	t.Run("ReturnsSubnetFromSmallestFreeBlock", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"10.0.0.0/28", "10.0.0.64/28", "10.0.0.96/27"}, []string{"a", "b", "c"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("10.0.0.0/24")
+
+		blocked := []string{}
+
+		// act
+		subnet, position := index.bestFreeSubnet(network, 28, func(candidate *net.IPNet, interval ipInterval) { blocked = append(blocked, candidate.String()) })
+
+		assert.Equal(t, "10.0.0.80/28", subnet.String())
+		assert.Equal(t, int64(5), position.Int64())
+		assert.Equal(t, []string{"10.0.0.0/28", "10.0.0.64/28", "10.0.0.96/28"}, blocked)
+	})
+
+	t.Run("ReportsCandidateBlockedByTwoUsedRangesOnlyOnce", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"10.0.0.0/25", "10.0.0.192/26", "10.0.3.0/24"}, []string{"a", "b", "c"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("10.0.0.0/22")
+
+		blocked := []string{}
+
+		// act
+		subnet, _ := index.bestFreeSubnet(network, 24, func(candidate *net.IPNet, interval ipInterval) { blocked = append(blocked, candidate.String()) })
+
+		assert.Equal(t, "10.0.1.0/24", subnet.String())
+		assert.Equal(t, []string{"10.0.0.0/24", "10.0.3.0/24"}, blocked)
+	})
+}
+
+func TestGetAllocator(t *testing.T) {
+
+	t.Run("ReturnsAllocatorForEverySupportedStrategy", func(t *testing.T) {
+
+		// act
+		firstFit, firstFitErr := getAllocator(networkv1.AllocationStrategyFirstFit)
+		bestFit, bestFitErr := getAllocator(networkv1.AllocationStrategyBestFit)
+		lastFit, lastFitErr := getAllocator(networkv1.AllocationStrategyLastFit)
+
+		assert.Nil(t, firstFitErr)
+		assert.Nil(t, bestFitErr)
+		assert.Nil(t, lastFitErr)
+		assert.Equal(t, firstFitAllocator{}, firstFit)
+		assert.Equal(t, bestFitAllocator{}, bestFit)
+		assert.Equal(t, lastFitAllocator{}, lastFit)
+	})
+
+	t.Run("ReturnsErrorForUnsupportedStrategy", func(t *testing.T) {
+
+		// act
+		_, err := getAllocator(networkv1.AllocationStrategy("random-fit"))
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Allocation strategy random-fit is not supported", err.Error())
+	})
+}
+
+func TestBlockedCandidates(t *testing.T) {
+
+	t.Run("CountsCandidateBlockedByTwoUsedRangesOnlyOnce", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"10.0.0.0/25", "10.0.0.192/26", "10.0.3.0/24"}, []string{"a", "b", "c"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("10.0.0.0/22")
+
+		// act
+		blocked, total, free := index.blockedCandidates(network, 24)
+
+		assert.Equal(t, int64(4), total.Int64())
+		assert.Equal(t, int64(2), free.Int64())
+		assert.Equal(t, 3, len(blocked))
+		assert.Equal(t, "10.0.0.0/24", blocked[1].FirstCIDR)
+		assert.Equal(t, "10.0.3.0/24", blocked[2].LastCIDR)
+	})
+
+	t.Run("IgnoresUsedRangesOutsideNetwork", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"10.0.0.0/8"}, []string{"a"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("172.28.0.0/14")
+
+		// act
+		blocked, total, free := index.blockedCandidates(network, 16)
+
+		assert.Equal(t, int64(4), total.Int64())
+		assert.Equal(t, int64(4), free.Int64())
+		assert.Equal(t, 0, len(blocked))
+	})
+}
+
+func TestSlotUsage(t *testing.T) {
+
+	t.Run("CountsPartiallyUsedSlotsAsFragmented", func(t *testing.T) {
+
+		index, err := newUsedRangeIndex(newTestUsedRanges([]string{"10.0.0.0/24", "10.0.1.0/25", "10.0.2.0/24", "10.0.3.0/24"}, []string{"a", "b", "c", "d"}))
+		assert.Nil(t, err)
+		_, network, _ := net.ParseCIDR("10.0.0.0/21")
+
+		// act
+		total, used, fragmented := index.slotUsage(network, 23)
+
+		assert.Equal(t, int64(4), total.Int64())
+		assert.Equal(t, int64(2), used.Int64())
+		assert.Equal(t, int64(1), fragmented.Int64())
+	})
+}
@@ -0,0 +1,122 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+	computev1 "google.golang.org/api/compute/v1"
+)
+
+// RangeOverlap is a pair of overlapping ranges that belong to different projects or networks and would conflict when those networks get peered
+type RangeOverlap struct {
+	Range            UsedRange `json:"range" yaml:"range"`
+	OverlappingRange UsedRange `json:"overlapping_range" yaml:"overlapping_range"`
+}
+
+func (s *service) Audit(ctx context.Context, filter ProjectFilter) (overlaps []RangeOverlap, err error) {
+
+	projects, err := s.getProjects(ctx, filter)
+	if err != nil {
+		return
+	}
+
+	subnetworks, err := s.gcpClient.GetProjectSubnetworks(ctx, projects)
+	if err != nil {
+		return
+	}
+
+	routes, err := s.gcpClient.GetProjectRoutes(ctx, projects)
+	if err != nil {
+		return
+	}
+
+	return s.AuditRanges(ctx, dedupeSubnetworks(subnetworks), dedupeRoutes(routes))
+}
+
+func (s *service) AuditRanges(ctx context.Context, subnetworks []*computev1.Subnetwork, routes []*computev1.Route) (overlaps []RangeOverlap, err error) {
+
+	log.Debug().Msgf("Auditing %v subnetworks and %v routes for overlapping ranges...", len(subnetworks), len(routes))
+
+	usedRanges := []UsedRange{}
+	for _, sn := range subnetworks {
+		if sn.IpCidrRange != "" {
+			usedRanges = append(usedRanges, newSubnetworkUsedRange(sn, sn.IpCidrRange, UsedRangeSourceSubnetwork, ""))
+		}
+		for _, sr := range sn.SecondaryIpRanges {
+			usedRanges = append(usedRanges, newSubnetworkUsedRange(sn, sr.IpCidrRange, UsedRangeSourceSecondaryRange, sr.RangeName))
+		}
+		if sn.Ipv6CidrRange != "" {
+			usedRanges = append(usedRanges, newSubnetworkUsedRange(sn, sn.Ipv6CidrRange, UsedRangeSourceSubnetwork, ""))
+		}
+	}
+	for _, r := range routes {
+		if isDefaultRoute(r.DestRange) {
+			continue
+		}
+		usedRanges = append(usedRanges, newRouteUsedRange(r))
+	}
+
+	return findOverlaps(usedRanges)
+}
+
+// findOverlaps returns all pairs of overlapping ranges from different projects or networks; it sorts the ranges by first address and sweeps over them, so only ranges that are still open get compared
+func findOverlaps(usedRanges []UsedRange) (overlaps []RangeOverlap, err error) {
+
+	type auditInterval struct {
+		first     *big.Int
+		last      *big.Int
+		bits      int
+		usedRange UsedRange
+	}
+
+	intervals := make([]auditInterval, 0, len(usedRanges))
+	for _, ur := range usedRanges {
+		_, ipnet, parseErr := net.ParseCIDR(ur.CIDR)
+		if parseErr != nil {
+			return nil, fmt.Errorf("Parsing %v failed", ur.CIDR)
+		}
+		first, last := ipNetToInterval(ipnet)
+		_, bits := ipnet.Mask.Size()
+		intervals = append(intervals, auditInterval{first: first, last: last, bits: bits, usedRange: ur})
+	}
+
+	// ipv4 and ipv6 addresses can have the same numeric value, so sort them apart
+	sort.SliceStable(intervals, func(i, j int) bool {
+		if intervals[i].bits != intervals[j].bits {
+			return intervals[i].bits < intervals[j].bits
+		}
+		return intervals[i].first.Cmp(intervals[j].first) < 0
+	})
+
+	overlaps = []RangeOverlap{}
+	open := []auditInterval{}
+	for _, iv := range intervals {
+
+		// close intervals ending before this one starts
+		stillOpen := open[:0]
+		for _, o := range open {
+			if o.bits == iv.bits && o.last.Cmp(iv.first) >= 0 {
+				stillOpen = append(stillOpen, o)
+			}
+		}
+		open = stillOpen
+
+		for _, o := range open {
+			if o.usedRange.Project == iv.usedRange.Project && o.usedRange.Network == iv.usedRange.Network {
+				continue
+			}
+			overlaps = append(overlaps, RangeOverlap{
+				Range:            o.usedRange,
+				OverlappingRange: iv.usedRange,
+			})
+		}
+
+		open = append(open, iv)
+	}
+
+	return overlaps, nil
+}
@@ -0,0 +1,168 @@
+package planner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	crmv1 "google.golang.org/api/cloudresourcemanager/v1"
+	computev1 "google.golang.org/api/compute/v1"
+)
+
+func TestAudit(t *testing.T) {
+
+	t.Run("ReturnsOverlapsForProjectsMatchingFilter", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+		subnetworks := []*computev1.Subnetwork{
+			{
+				Name:        "subnet-a",
+				IpCidrRange: "10.0.0.0/16",
+				SelfLink:    "https://www.googleapis.com/compute/v1/projects/project-a/regions/europe-west1/subnetworks/subnet-a",
+				Network:     "https://www.googleapis.com/compute/v1/projects/project-a/global/networks/default",
+			},
+			{
+				Name:        "subnet-b",
+				IpCidrRange: "10.0.4.0/22",
+				SelfLink:    "https://www.googleapis.com/compute/v1/projects/project-b/regions/europe-west1/subnetworks/subnet-b",
+				Network:     "https://www.googleapis.com/compute/v1/projects/project-b/global/networks/default",
+			},
+		}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(ctx, []string{filter.Filter}).
+			Return(projects, nil).
+			Times(1)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(ctx, projects).
+			Return([]*crmv1.Project{}, nil).
+			Times(1)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(ctx, projects).
+			Return(subnetworks, nil).
+			Times(1)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(ctx, projects).
+			Return([]*computev1.Route{}, nil).
+			Times(1)
+
+		// act
+		overlaps, err := service.Audit(ctx, filter)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(overlaps))
+		assert.Equal(t, "subnet-a", overlaps[0].Range.Name)
+		assert.Equal(t, "project-a", overlaps[0].Range.Project)
+		assert.Equal(t, "subnet-b", overlaps[0].OverlappingRange.Name)
+		assert.Equal(t, "project-b", overlaps[0].OverlappingRange.Project)
+	})
+}
+
+func TestAuditRanges(t *testing.T) {
+
+	t.Run("IgnoresOverlapsWithinTheSameProjectAndNetwork", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		subnetworks := []*computev1.Subnetwork{
+			{
+				Name:        "subnet-a",
+				IpCidrRange: "10.0.0.0/16",
+				SelfLink:    "https://www.googleapis.com/compute/v1/projects/project-a/regions/europe-west1/subnetworks/subnet-a",
+				Network:     "https://www.googleapis.com/compute/v1/projects/project-a/global/networks/default",
+			},
+		}
+		routes := []*computev1.Route{
+			{
+				Name:      "default-route-subnet-a",
+				DestRange: "10.0.0.0/16",
+				SelfLink:  "https://www.googleapis.com/compute/v1/projects/project-a/global/routes/default-route-subnet-a",
+				Network:   "https://www.googleapis.com/compute/v1/projects/project-a/global/networks/default",
+			},
+			{
+				Name:      "default-internet-route",
+				DestRange: "0.0.0.0/0",
+				SelfLink:  "https://www.googleapis.com/compute/v1/projects/project-b/global/routes/default-internet-route",
+				Network:   "https://www.googleapis.com/compute/v1/projects/project-b/global/networks/default",
+			},
+		}
+
+		// act
+		overlaps, err := service.AuditRanges(ctx, subnetworks, routes)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(overlaps))
+	})
+
+	t.Run("ReturnsEveryOverlappingPairIncludingSecondaryRangesAndRoutes", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		subnetworks := []*computev1.Subnetwork{
+			{
+				Name:        "subnet-a",
+				IpCidrRange: "10.0.0.0/24",
+				SecondaryIpRanges: []*computev1.SubnetworkSecondaryRange{
+					{
+						RangeName:   "pods",
+						IpCidrRange: "10.4.0.0/14",
+					},
+				},
+				SelfLink: "https://www.googleapis.com/compute/v1/projects/project-a/regions/europe-west1/subnetworks/subnet-a",
+				Network:  "https://www.googleapis.com/compute/v1/projects/project-a/global/networks/default",
+			},
+			{
+				Name:        "subnet-b",
+				IpCidrRange: "10.5.0.0/24",
+				SelfLink:    "https://www.googleapis.com/compute/v1/projects/project-a/regions/europe-west1/subnetworks/subnet-b",
+				Network:     "https://www.googleapis.com/compute/v1/projects/project-a/global/networks/other",
+			},
+		}
+		routes := []*computev1.Route{
+			{
+				Name:      "vpn-route",
+				DestRange: "10.6.0.0/16",
+				SelfLink:  "https://www.googleapis.com/compute/v1/projects/project-c/global/routes/vpn-route",
+				Network:   "https://www.googleapis.com/compute/v1/projects/project-c/global/networks/default",
+			},
+		}
+
+		// act
+		overlaps, err := service.AuditRanges(ctx, subnetworks, routes)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(overlaps))
+		assert.Equal(t, "subnet-a/pods", overlaps[0].Range.Name)
+		assert.Equal(t, UsedRangeSourceSecondaryRange, overlaps[0].Range.Source)
+		assert.Equal(t, "subnet-b", overlaps[0].OverlappingRange.Name)
+		assert.Equal(t, "subnet-a/pods", overlaps[1].Range.Name)
+		assert.Equal(t, "vpn-route", overlaps[1].OverlappingRange.Name)
+	})
+}
@@ -0,0 +1,39 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrReservationConflict is returned when the reservations have been modified by someone else between reading and writing them; the operation can be retried
+	ErrReservationConflict = wrapError{msg: "The reservations have been modified concurrently"}
+
+	// ErrRangeAlreadyReserved is returned when a range overlaps with an active reservation
+	ErrRangeAlreadyReserved = wrapError{msg: "The range is already reserved"}
+)
+
+type wrapError struct {
+	err error
+	msg string
+}
+
+func (err wrapError) Error() string {
+	if err.err != nil {
+		return fmt.Sprintf("%s: %v", err.msg, err.err)
+	}
+	return err.msg
+}
+
+func (err wrapError) wrap(inner error) error {
+	return wrapError{msg: err.msg, err: inner}
+}
+
+func (err wrapError) Unwrap() error {
+	return err.err
+}
+
+func (err wrapError) Is(target error) bool {
+	ts := target.Error()
+	return ts == err.msg || strings.HasPrefix(ts, err.msg+": ")
+}
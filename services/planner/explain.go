@@ -0,0 +1,108 @@
+package planner
+
+import (
+	"context"
+	"math/big"
+	"net"
+
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/rs/zerolog/log"
+	computev1 "google.golang.org/api/compute/v1"
+)
+
+// Explanation describes for a network type how many candidate ranges of its range config are free, which resources block the others and which free candidate the allocation strategy picks
+type Explanation struct {
+	Type               networkv1.Type               `json:"type" yaml:"type"`
+	RangeConfig        networkv1.RangeConfig        `json:"range_config" yaml:"range_config"`
+	TotalCandidates    *big.Int                     `json:"total_candidates" yaml:"total_candidates"`
+	FreeCandidates     *big.Int                     `json:"free_candidates" yaml:"free_candidates"`
+	Suggestion         string                       `json:"suggestion,omitempty" yaml:"suggestion,omitempty"`
+	AllocationStrategy networkv1.AllocationStrategy `json:"allocation_strategy" yaml:"allocation_strategy"`
+	BlockedCandidates  []BlockedCandidates          `json:"blocked_candidates" yaml:"blocked_candidates"`
+}
+
+// BlockedCandidates is a consecutive series of candidate ranges from FirstCIDR up to and including LastCIDR, blocked by the same used ranges
+type BlockedCandidates struct {
+	FirstCIDR string      `json:"first_cidr" yaml:"first_cidr"`
+	LastCIDR  string      `json:"last_cidr" yaml:"last_cidr"`
+	Count     *big.Int    `json:"count" yaml:"count"`
+	BlockedBy []UsedRange `json:"blocked_by" yaml:"blocked_by"`
+}
+
+func (s *service) Explain(ctx context.Context, filter ProjectFilter, selector networkv1.Selector, networkTypes ...networkv1.Type) (explanations []Explanation, err error) {
+
+	config, err := s.getValidConfig(ctx)
+	if err != nil {
+		return
+	}
+
+	// fail before retrieving anything from gcp if any of the types can't be suggested
+	networkTypes, err = s.getNetworkTypes(config, selector, networkv1.SubnetSize{}, networkTypes)
+	if err != nil {
+		return
+	}
+
+	subnetworks, routes, usedRanges, err := s.getUsedResources(ctx, filter)
+	if err != nil {
+		return
+	}
+
+	explanations = []Explanation{}
+	for _, t := range networkTypes {
+		explanation, err := s.ExplainSingleNetworkRange(ctx, config.RangeConfigs, subnetworks, routes, usedRanges, t, selector)
+		if err != nil {
+			return explanations, err
+		}
+
+		explanations = append(explanations, explanation)
+	}
+
+	return
+}
+
+func (s *service) ExplainSingleNetworkRange(ctx context.Context, rangeConfigs []networkv1.RangeConfig, subnetworks []*computev1.Subnetwork, routes []*computev1.Route, usedRanges []UsedRange, networkType networkv1.Type, selector networkv1.Selector) (explanation Explanation, err error) {
+
+	log.Debug().Msgf("Explaining subnetwork ranges for network type %v (with %v range configs and %v subnetworks and %v routes and %v used ranges)...", networkType, len(rangeConfigs), len(subnetworks), len(routes), len(usedRanges))
+
+	rangeConfig, err := s.getRangeConfig(rangeConfigs, networkType, selector)
+	if err != nil {
+		return
+	}
+
+	applicableUsedRanges, err := s.getApplicableUsedRanges(rangeConfig, subnetworks, routes, usedRanges)
+	if err != nil {
+		return
+	}
+
+	index, err := newUsedRangeIndex(applicableUsedRanges)
+	if err != nil {
+		return
+	}
+
+	_, networkIPnet, err := net.ParseCIDR(rangeConfig.NetworkCIDR)
+	if err != nil {
+		return
+	}
+
+	blocked, total, free := index.blockedCandidates(networkIPnet, rangeConfig.SubnetMask)
+
+	explanation = Explanation{
+		Type:              networkType,
+		RangeConfig:       rangeConfig,
+		TotalCandidates:   total,
+		FreeCandidates:    free,
+		BlockedCandidates: blocked,
+	}
+
+	explanation.AllocationStrategy = rangeConfig.GetAllocationStrategy(s.allocationStrategy)
+	allocator, err := getAllocator(explanation.AllocationStrategy)
+	if err != nil {
+		return
+	}
+	subnetRange, _ := allocator.freeSubnet(index, networkIPnet, rangeConfig.SubnetMask, nil)
+	if subnetRange != nil {
+		explanation.Suggestion = subnetRange.String()
+	}
+
+	return
+}
@@ -0,0 +1,105 @@
+package planner
+
+import (
+	"context"
+	"testing"
+
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	computev1 "google.golang.org/api/compute/v1"
+)
+
+func TestExplainSingleNetworkRange(t *testing.T) {
+
+	t.Run("ReturnsBlockingSubnetworksAndRoutesWithTheirProjectRegionAndNetwork", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfigs := []networkv1.RangeConfig{
+			{
+				Type:        networkv1.TypeNode,
+				RangeType:   networkv1.RangeTypePrimary,
+				NetworkCIDR: "172.28.0.0/14",
+				SubnetMask:  16,
+			},
+		}
+		subnetworks := []*computev1.Subnetwork{
+			{
+				Name:        "gke-nodes",
+				IpCidrRange: "172.28.0.0/20",
+				SelfLink:    "https://www.googleapis.com/compute/v1/projects/my-project/regions/europe-west1/subnetworks/gke-nodes",
+				Region:      "https://www.googleapis.com/compute/v1/projects/my-project/regions/europe-west1",
+				Network:     "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/my-network",
+			},
+		}
+		routes := []*computev1.Route{
+			{
+				Name:      "peering-route",
+				DestRange: "172.30.0.0/16",
+				SelfLink:  "https://www.googleapis.com/compute/v1/projects/other-project/global/routes/peering-route",
+				Network:   "https://www.googleapis.com/compute/v1/projects/other-project/global/networks/other-network",
+			},
+		}
+		networkType := networkv1.TypeNode
+
+		// act
+		explanation, err := service.ExplainSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), explanation.TotalCandidates.Int64())
+		assert.Equal(t, int64(2), explanation.FreeCandidates.Int64())
+		assert.Equal(t, "172.29.0.0/16", explanation.Suggestion)
+		assert.Equal(t, networkv1.AllocationStrategyFirstFit, explanation.AllocationStrategy)
+		assert.Equal(t, 2, len(explanation.BlockedCandidates))
+		assert.Equal(t, "172.28.0.0/16", explanation.BlockedCandidates[0].FirstCIDR)
+		assert.Equal(t, []UsedRange{{CIDR: "172.28.0.0/20", Source: UsedRangeSourceSubnetwork, Name: "gke-nodes", Project: "my-project", Region: "europe-west1", Network: "my-network"}}, explanation.BlockedCandidates[0].BlockedBy)
+		assert.Equal(t, "172.30.0.0/16", explanation.BlockedCandidates[1].FirstCIDR)
+		assert.Equal(t, []UsedRange{{CIDR: "172.30.0.0/16", Source: UsedRangeSourceRoute, Name: "peering-route", Project: "other-project", Network: "other-network"}}, explanation.BlockedCandidates[1].BlockedBy)
+	})
+
+	t.Run("ReturnsNoSuggestionIfAllCandidatesAreBlocked", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfigs := []networkv1.RangeConfig{
+			{
+				Type:        networkv1.TypeNode,
+				RangeType:   networkv1.RangeTypePrimary,
+				NetworkCIDR: "172.28.0.0/14",
+				SubnetMask:  15,
+			},
+		}
+		usedRanges := []UsedRange{
+			{
+				CIDR:   "172.28.0.0/14",
+				Source: UsedRangeSourceReservation,
+				Name:   "reserved by someone",
+			},
+		}
+		networkType := networkv1.TypeNode
+
+		// act
+		explanation, err := service.ExplainSingleNetworkRange(ctx, rangeConfigs, nil, nil, usedRanges, networkType, networkv1.Selector{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), explanation.TotalCandidates.Int64())
+		assert.Equal(t, int64(0), explanation.FreeCandidates.Int64())
+		assert.Equal(t, "", explanation.Suggestion)
+		assert.Equal(t, 1, len(explanation.BlockedCandidates))
+		assert.Equal(t, "172.28.0.0/15", explanation.BlockedCandidates[0].FirstCIDR)
+		assert.Equal(t, "172.30.0.0/15", explanation.BlockedCandidates[0].LastCIDR)
+		assert.Equal(t, int64(2), explanation.BlockedCandidates[0].Count.Int64())
+	})
+}
@@ -52,36 +52,184 @@ func (mr *MockServiceMockRecorder) LoadConfig(ctx interface{}) *gomock.Call {
 }
 
 // Suggest mocks base method
-func (m *MockService) Suggest(ctx context.Context, filter string, networkTypes ...network.Type) (map[network.Type]*net.IPNet, error) {
+func (m *MockService) Suggest(ctx context.Context, filter ProjectFilter, selector network.Selector, count int, size network.SubnetSize, networkTypes ...network.Type) ([]network.Suggestion, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx, filter}
+	varargs := []interface{}{ctx, filter, selector, count, size}
 	for _, a := range networkTypes {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "Suggest", varargs...)
-	ret0, _ := ret[0].(map[network.Type]*net.IPNet)
+	ret0, _ := ret[0].([]network.Suggestion)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Suggest indicates an expected call of Suggest
-func (mr *MockServiceMockRecorder) Suggest(ctx, filter interface{}, networkTypes ...interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Suggest(ctx, filter, selector, count, size interface{}, networkTypes ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx, filter}, networkTypes...)
+	varargs := append([]interface{}{ctx, filter, selector, count, size}, networkTypes...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suggest", reflect.TypeOf((*MockService)(nil).Suggest), varargs...)
 }
 
 // SuggestSingleNetworkRange mocks base method
-func (m *MockService) SuggestSingleNetworkRange(ctx context.Context, rangeConfigs []network.RangeConfig, subnetworks []*compute.Subnetwork, routes []*compute.Route, networkType network.Type) (*net.IPNet, error) {
+func (m *MockService) SuggestSingleNetworkRange(ctx context.Context, rangeConfigs []network.RangeConfig, subnetworks []*compute.Subnetwork, routes []*compute.Route, usedRanges []UsedRange, networkType network.Type, selector network.Selector, size network.SubnetSize) (*net.IPNet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SuggestSingleNetworkRange", ctx, rangeConfigs, subnetworks, routes, networkType)
+	ret := m.ctrl.Call(m, "SuggestSingleNetworkRange", ctx, rangeConfigs, subnetworks, routes, usedRanges, networkType, selector, size)
 	ret0, _ := ret[0].(*net.IPNet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SuggestSingleNetworkRange indicates an expected call of SuggestSingleNetworkRange
-func (mr *MockServiceMockRecorder) SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, networkType interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, usedRanges, networkType, selector, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestSingleNetworkRange", reflect.TypeOf((*MockService)(nil).SuggestSingleNetworkRange), ctx, rangeConfigs, subnetworks, routes, networkType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestSingleNetworkRange", reflect.TypeOf((*MockService)(nil).SuggestSingleNetworkRange), ctx, rangeConfigs, subnetworks, routes, usedRanges, networkType, selector, size)
+}
+
+// Explain mocks base method
+func (m *MockService) Explain(ctx context.Context, filter ProjectFilter, selector network.Selector, networkTypes ...network.Type) ([]Explanation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, filter, selector}
+	for _, a := range networkTypes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Explain", varargs...)
+	ret0, _ := ret[0].([]Explanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Explain indicates an expected call of Explain
+func (mr *MockServiceMockRecorder) Explain(ctx, filter, selector interface{}, networkTypes ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, filter, selector}, networkTypes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockService)(nil).Explain), varargs...)
+}
+
+// ExplainSingleNetworkRange mocks base method
+func (m *MockService) ExplainSingleNetworkRange(ctx context.Context, rangeConfigs []network.RangeConfig, subnetworks []*compute.Subnetwork, routes []*compute.Route, usedRanges []UsedRange, networkType network.Type, selector network.Selector) (Explanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainSingleNetworkRange", ctx, rangeConfigs, subnetworks, routes, usedRanges, networkType, selector)
+	ret0, _ := ret[0].(Explanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainSingleNetworkRange indicates an expected call of ExplainSingleNetworkRange
+func (mr *MockServiceMockRecorder) ExplainSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, usedRanges, networkType, selector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainSingleNetworkRange", reflect.TypeOf((*MockService)(nil).ExplainSingleNetworkRange), ctx, rangeConfigs, subnetworks, routes, usedRanges, networkType, selector)
+}
+
+// Usage mocks base method
+func (m *MockService) Usage(ctx context.Context, filter ProjectFilter) ([]RangeConfigUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx, filter)
+	ret0, _ := ret[0].([]RangeConfigUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage
+func (mr *MockServiceMockRecorder) Usage(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockService)(nil).Usage), ctx, filter)
+}
+
+// UsageSingleRangeConfig mocks base method
+func (m *MockService) UsageSingleRangeConfig(ctx context.Context, rangeConfig network.RangeConfig, subnetworks []*compute.Subnetwork, routes []*compute.Route, usedRanges []UsedRange) (RangeConfigUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsageSingleRangeConfig", ctx, rangeConfig, subnetworks, routes, usedRanges)
+	ret0, _ := ret[0].(RangeConfigUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UsageSingleRangeConfig indicates an expected call of UsageSingleRangeConfig
+func (mr *MockServiceMockRecorder) UsageSingleRangeConfig(ctx, rangeConfig, subnetworks, routes, usedRanges interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsageSingleRangeConfig", reflect.TypeOf((*MockService)(nil).UsageSingleRangeConfig), ctx, rangeConfig, subnetworks, routes, usedRanges)
+}
+
+// Audit mocks base method
+func (m *MockService) Audit(ctx context.Context, filter ProjectFilter) ([]RangeOverlap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Audit", ctx, filter)
+	ret0, _ := ret[0].([]RangeOverlap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Audit indicates an expected call of Audit
+func (mr *MockServiceMockRecorder) Audit(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Audit", reflect.TypeOf((*MockService)(nil).Audit), ctx, filter)
+}
+
+// AuditRanges mocks base method
+func (m *MockService) AuditRanges(ctx context.Context, subnetworks []*compute.Subnetwork, routes []*compute.Route) ([]RangeOverlap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditRanges", ctx, subnetworks, routes)
+	ret0, _ := ret[0].([]RangeOverlap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuditRanges indicates an expected call of AuditRanges
+func (mr *MockServiceMockRecorder) AuditRanges(ctx, subnetworks, routes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditRanges", reflect.TypeOf((*MockService)(nil).AuditRanges), ctx, subnetworks, routes)
+}
+
+// ListReservations mocks base method
+func (m *MockService) ListReservations(ctx context.Context) ([]network.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReservations", ctx)
+	ret0, _ := ret[0].([]network.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReservations indicates an expected call of ListReservations
+func (mr *MockServiceMockRecorder) ListReservations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReservations", reflect.TypeOf((*MockService)(nil).ListReservations), ctx)
+}
+
+// Reserve mocks base method
+func (m *MockService) Reserve(ctx context.Context, reservations ...network.Reservation) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range reservations {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Reserve", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reserve indicates an expected call of Reserve
+func (mr *MockServiceMockRecorder) Reserve(ctx interface{}, reservations ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, reservations...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockService)(nil).Reserve), varargs...)
+}
+
+// Release mocks base method
+func (m *MockService) Release(ctx context.Context, cidrs ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range cidrs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Release", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release
+func (mr *MockServiceMockRecorder) Release(ctx interface{}, cidrs ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, cidrs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockService)(nil).Release), varargs...)
 }
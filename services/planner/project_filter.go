@@ -0,0 +1,128 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+
+	crmv1 "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// ProjectFilter selects the projects to retrieve used ranges for
+type ProjectFilter struct {
+	// Filter is a resource manager list filter like labels.environment:dev, see https://cloud.google.com/resource-manager/reference/rest/v1/projects/list#query-parameters
+	Filter string
+
+	// OrganizationID and FolderIDs limit Filter to projects in the organization or folders, including their sub-folders
+	OrganizationID string
+	FolderIDs      []string
+
+	// ProjectIDs are included in addition to the projects matching Filter; when only ProjectIDs are set no other projects are included
+	ProjectIDs []string
+
+	// ExcludedProjectIDs are left out, even if they match or are a shared vpc host
+	ExcludedProjectIDs []string
+}
+
+// getProjects returns the projects matching the filter together with the shared vpc host projects they're attached to
+func (s *service) getProjects(ctx context.Context, filter ProjectFilter) (projects []*crmv1.Project, err error) {
+
+	excluded := map[string]bool{}
+	for _, id := range filter.ExcludedProjectIDs {
+		excluded[id] = true
+	}
+
+	projects = []*crmv1.Project{}
+	projectIDs := map[string]bool{}
+	addProjects := func(matchedProjects []*crmv1.Project) {
+		for _, p := range matchedProjects {
+			if excluded[p.ProjectId] || projectIDs[p.ProjectId] {
+				continue
+			}
+			projectIDs[p.ProjectId] = true
+			projects = append(projects, p)
+		}
+	}
+
+	if filter.Filter != "" || filter.OrganizationID != "" || len(filter.FolderIDs) > 0 || len(filter.ProjectIDs) == 0 {
+		parentFilters, parentErr := s.getParentFilters(ctx, filter)
+		if parentErr != nil {
+			return projects, parentErr
+		}
+
+		if len(parentFilters) == 0 {
+			matchedProjects, listErr := s.gcpClient.GetProjectByLabels(ctx, []string{filter.Filter})
+			if listErr != nil {
+				return projects, listErr
+			}
+			addProjects(matchedProjects)
+		}
+
+		// the list filter can't match projects in any of several parents, so list them per parent
+		for _, pf := range parentFilters {
+			filters := []string{pf}
+			if filter.Filter != "" {
+				filters = append([]string{filter.Filter}, pf)
+			}
+
+			matchedProjects, listErr := s.gcpClient.GetProjectByLabels(ctx, filters)
+			if listErr != nil {
+				return projects, listErr
+			}
+			addProjects(matchedProjects)
+		}
+	}
+
+	if len(filter.ProjectIDs) > 0 {
+		includedProjects, getErr := s.gcpClient.GetProjectsByID(ctx, filter.ProjectIDs)
+		if getErr != nil {
+			return projects, getErr
+		}
+		addProjects(includedProjects)
+	}
+
+	// service projects use the subnetworks of their shared vpc host, so include the host projects as well
+	hostProjects, err := s.gcpClient.GetSharedVPCHostProjects(ctx, projects)
+	if err != nil {
+		return
+	}
+	addProjects(hostProjects)
+
+	return
+}
+
+// getParentFilters returns a list filter for the organization and each of the folders and their sub-folders
+func (s *service) getParentFilters(ctx context.Context, filter ProjectFilter) (parentFilters []string, err error) {
+
+	folderIDs := []string{}
+	if filter.OrganizationID != "" {
+		parentFilters = append(parentFilters, fmt.Sprintf("parent.type:organization parent.id:%v", filter.OrganizationID))
+
+		subFolderIDs, folderErr := s.gcpClient.GetFolderIDs(ctx, "organizations/"+filter.OrganizationID)
+		if folderErr != nil {
+			return parentFilters, folderErr
+		}
+		folderIDs = append(folderIDs, subFolderIDs...)
+	}
+
+	for _, id := range filter.FolderIDs {
+		folderIDs = append(folderIDs, id)
+
+		subFolderIDs, folderErr := s.gcpClient.GetFolderIDs(ctx, "folders/"+id)
+		if folderErr != nil {
+			return parentFilters, folderErr
+		}
+		folderIDs = append(folderIDs, subFolderIDs...)
+	}
+
+	// folders are listed more than once if they're in the organization as well as in the folders
+	seen := map[string]bool{}
+	for _, id := range folderIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		parentFilters = append(parentFilters, fmt.Sprintf("parent.type:folder parent.id:%v", id))
+	}
+
+	return
+}
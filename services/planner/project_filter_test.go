@@ -0,0 +1,125 @@
+package planner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	crmv1 "google.golang.org/api/cloudresourcemanager/v1"
+)
+
+func TestGetProjects(t *testing.T) {
+
+	t.Run("ReturnsProjectsMatchingFilterAndTheirSharedVPCHosts", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		plannerService := &service{gcpClient: gcpClientMock}
+		projects := []*crmv1.Project{{ProjectId: "service-project"}}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Eq([]string{"labels.environment=dev"})).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{{ProjectId: "host-project"}}, nil)
+
+		// act
+		matchedProjects, err := plannerService.getProjects(ctx, ProjectFilter{Filter: "labels.environment=dev"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(matchedProjects))
+		assert.Equal(t, "service-project", matchedProjects[0].ProjectId)
+		assert.Equal(t, "host-project", matchedProjects[1].ProjectId)
+	})
+
+	t.Run("ReturnsOnlyIncludedProjectsIfNoOtherFilterIsSet", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		plannerService := &service{gcpClient: gcpClientMock}
+		projects := []*crmv1.Project{{ProjectId: "project-a"}, {ProjectId: "project-b"}}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectsByID(gomock.Any(), gomock.Eq([]string{"project-a", "project-b"})).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		// act
+		matchedProjects, err := plannerService.getProjects(ctx, ProjectFilter{ProjectIDs: []string{"project-a", "project-b"}})
+
+		assert.Nil(t, err)
+		assert.Equal(t, projects, matchedProjects)
+	})
+
+	t.Run("ReturnsProjectsInOrganizationAndSubFoldersWithoutExcludedProjects", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		plannerService := &service{gcpClient: gcpClientMock}
+
+		gcpClientMock.
+			EXPECT().
+			GetFolderIDs(gomock.Any(), "organizations/123").
+			Return([]string{"456", "789"}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetFolderIDs(gomock.Any(), "folders/456").
+			Return([]string{"789"}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Eq([]string{"labels.team:a", "parent.type:organization parent.id:123"})).
+			Return([]*crmv1.Project{{ProjectId: "project-a"}}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Eq([]string{"labels.team:a", "parent.type:folder parent.id:456"})).
+			Return([]*crmv1.Project{{ProjectId: "project-b"}, {ProjectId: "sandbox"}}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Eq([]string{"labels.team:a", "parent.type:folder parent.id:789"})).
+			Return([]*crmv1.Project{{ProjectId: "project-c"}}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Any()).
+			Return([]*crmv1.Project{{ProjectId: "sandbox-host"}}, nil)
+
+		// act
+		matchedProjects, err := plannerService.getProjects(ctx, ProjectFilter{
+			Filter:             "labels.team:a",
+			OrganizationID:     "123",
+			FolderIDs:          []string{"456"},
+			ExcludedProjectIDs: []string{"sandbox", "sandbox-host"},
+		})
+
+		assert.Nil(t, err)
+		projectIDs := []string{}
+		for _, p := range matchedProjects {
+			projectIDs = append(projectIDs, p.ProjectId)
+		}
+		assert.Equal(t, []string{"project-a", "project-b", "project-c"}, projectIDs)
+	})
+}
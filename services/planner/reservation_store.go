@@ -0,0 +1,189 @@
+package planner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/rs/zerolog/log"
+)
+
+// ReservationStore persists reserved network ranges, so suggestions made before the ranges are actually in use don't get handed out twice
+type ReservationStore interface {
+	ListReservations(ctx context.Context) (reservations []networkv1.Reservation, err error)
+	Reserve(ctx context.Context, reservations ...networkv1.Reservation) (err error)
+	Release(ctx context.Context, cidrs ...string) (err error)
+}
+
+// NewReservationStore returns a gcs backed store for gs://bucket/object locations and a file backed store for any other location
+func NewReservationStore(ctx context.Context, location, storageEndpoint string) (ReservationStore, error) {
+	if location == "" {
+		return nil, fmt.Errorf("Location for reservations is empty")
+	}
+
+	if strings.HasPrefix(location, "gs://") {
+		bucketAndObject := strings.SplitN(strings.TrimPrefix(location, "gs://"), "/", 2)
+		if len(bucketAndObject) != 2 || bucketAndObject[0] == "" || bucketAndObject[1] == "" {
+			return nil, fmt.Errorf("Location %v for reservations is invalid; please use gs://bucket/object", location)
+		}
+
+		return NewGCSReservationStore(ctx, bucketAndObject[0], bucketAndObject[1], storageEndpoint)
+	}
+
+	return NewFileReservationStore(location), nil
+}
+
+// ledgerBackend reads and writes the raw reservations document; write only succeeds if the document is still at the generation returned by read and returns ErrReservationConflict otherwise
+type ledgerBackend interface {
+	read(ctx context.Context) (data []byte, generation int64, err error)
+	write(ctx context.Context, data []byte, generation int64) (err error)
+}
+
+type ledgerDocument struct {
+	Reservations []networkv1.Reservation `json:"reservations"`
+}
+
+// ledger implements ReservationStore on top of any backend able to read and write a single document
+type ledger struct {
+	backend ledgerBackend
+}
+
+func (l *ledger) ListReservations(ctx context.Context) (reservations []networkv1.Reservation, err error) {
+	document, _, err := l.load(ctx)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	reservations = []networkv1.Reservation{}
+	for _, r := range document.Reservations {
+		if r.IsActive(now) {
+			reservations = append(reservations, r)
+		}
+	}
+
+	return
+}
+
+func (l *ledger) Reserve(ctx context.Context, reservations ...networkv1.Reservation) (err error) {
+	document, generation, err := l.load(ctx)
+	if err != nil {
+		return
+	}
+
+	document.Reservations, err = addReservations(document.Reservations, time.Now().UTC(), reservations...)
+	if err != nil {
+		return
+	}
+
+	return l.save(ctx, document, generation)
+}
+
+func (l *ledger) Release(ctx context.Context, cidrs ...string) (err error) {
+	document, generation, err := l.load(ctx)
+	if err != nil {
+		return
+	}
+
+	document.Reservations, err = removeReservations(document.Reservations, cidrs...)
+	if err != nil {
+		return
+	}
+
+	return l.save(ctx, document, generation)
+}
+
+func (l *ledger) load(ctx context.Context) (document ledgerDocument, generation int64, err error) {
+	data, generation, err := l.backend.read(ctx)
+	if err != nil {
+		return
+	}
+
+	if len(data) == 0 {
+		return
+	}
+
+	err = json.Unmarshal(data, &document)
+	if err != nil {
+		return document, generation, fmt.Errorf("Reservations document is invalid: %w", err)
+	}
+
+	return
+}
+
+func (l *ledger) save(ctx context.Context, document ledgerDocument, generation int64) (err error) {
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return
+	}
+
+	return l.backend.write(ctx, data, generation)
+}
+
+// addReservations drops expired reservations and appends the new ones if they don't overlap with any active reservation
+func addReservations(existing []networkv1.Reservation, now time.Time, reservations ...networkv1.Reservation) (updated []networkv1.Reservation, err error) {
+
+	updated = []networkv1.Reservation{}
+	for _, r := range existing {
+		if r.IsActive(now) {
+			updated = append(updated, r)
+		} else {
+			log.Debug().Msgf("Dropping expired reservation for %v", r.CIDR)
+		}
+	}
+
+	for _, r := range reservations {
+		_, ipnet, parseErr := net.ParseCIDR(r.CIDR)
+		if parseErr != nil {
+			return existing, fmt.Errorf("Reservation cidr %v is invalid: %w", r.CIDR, parseErr)
+		}
+		r.CIDR = ipnet.String()
+
+		for _, u := range updated {
+			overlap, overlapErr := rangesOverlap(r.CIDR, u.CIDR)
+			if overlapErr != nil {
+				return existing, overlapErr
+			}
+			if overlap {
+				return existing, ErrRangeAlreadyReserved.wrap(fmt.Errorf("Range %v overlaps with range %v reserved by %v", r.CIDR, u.CIDR, u.Owner))
+			}
+		}
+
+		if r.ReservedAt.IsZero() {
+			r.ReservedAt = now
+		}
+
+		updated = append(updated, r)
+	}
+
+	return updated, nil
+}
+
+// removeReservations removes the reservations for the cidrs and fails if any of them isn't reserved
+func removeReservations(existing []networkv1.Reservation, cidrs ...string) (updated []networkv1.Reservation, err error) {
+
+	updated = existing
+	for _, c := range cidrs {
+		_, ipnet, parseErr := net.ParseCIDR(c)
+		if parseErr != nil {
+			return existing, fmt.Errorf("Release cidr %v is invalid: %w", c, parseErr)
+		}
+
+		remaining := []networkv1.Reservation{}
+		for _, r := range updated {
+			if r.CIDR != ipnet.String() {
+				remaining = append(remaining, r)
+			}
+		}
+		if len(remaining) == len(updated) {
+			return existing, fmt.Errorf("Range %v is not reserved", ipnet.String())
+		}
+		updated = remaining
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,215 @@
+package planner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultLockTTL is the age after which a lock file is considered abandoned by a crashed process
+	defaultLockTTL = 30 * time.Second
+)
+
+// NewFileReservationStore returns a ReservationStore keeping reservations in a local json file
+func NewFileReservationStore(path string) ReservationStore {
+	return &ledger{
+		backend: &fileLedgerBackend{
+			path:    path,
+			lockTTL: defaultLockTTL,
+		},
+	}
+}
+
+// fileLedgerBackend uses a hash of the file content as generation and a lock file to make comparing and writing atomic
+type fileLedgerBackend struct {
+	path    string
+	lockTTL time.Duration
+}
+
+func (b *fileLedgerBackend) read(ctx context.Context) (data []byte, generation int64, err error) {
+	log.Debug().Msgf("Reading reservations from %v...", b.path)
+
+	data, err = ioutil.ReadFile(b.path)
+	if err != nil && os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return
+	}
+
+	return data, b.getGeneration(data), nil
+}
+
+func (b *fileLedgerBackend) write(ctx context.Context, data []byte, generation int64) (err error) {
+	log.Debug().Msgf("Writing reservations to %v...", b.path)
+
+	if dir := filepath.Dir(b.path); dir != "" {
+		err = os.MkdirAll(dir, 0755)
+		if err != nil {
+			return
+		}
+	}
+
+	lock, err := b.lock()
+	if err != nil {
+		return
+	}
+	defer lock.unlock()
+
+	// check whether the file has been modified since it was read
+	_, currentGeneration, err := b.read(ctx)
+	if err != nil {
+		return
+	}
+	if currentGeneration != generation {
+		return ErrReservationConflict.wrap(fmt.Errorf("File %v has been modified since it was read", b.path))
+	}
+
+	// write to a temporary file first and rename it, so readers never see a partially written file
+	tmpPath := fmt.Sprintf("%v.%v.tmp", b.path, lock.token)
+	err = ioutil.WriteFile(tmpPath, data, 0644)
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmpPath)
+
+	// make sure the lock hasn't been taken over in the meantime
+	err = lock.confirm()
+	if err != nil {
+		return
+	}
+
+	return os.Rename(tmpPath, b.path)
+}
+
+// fileLock is a lock file holding a token unique to its owner, so the owner can check it still holds the lock
+type fileLock struct {
+	path  string
+	token string
+}
+
+// lock creates a lock file next to the reservations file; lock files older than the ttl are taken over
+func (b *fileLedgerBackend) lock() (lock *fileLock, err error) {
+	token, err := newLockToken()
+	if err != nil {
+		return
+	}
+
+	lock = &fileLock{
+		path:  b.path + ".lock",
+		token: token,
+	}
+
+	err = lock.create()
+	if err == nil {
+		return lock, nil
+	}
+	if !os.IsExist(err) {
+		return nil, err
+	}
+
+	info, statErr := os.Stat(lock.path)
+	if statErr != nil || time.Since(info.ModTime()) < b.lockTTL {
+		return nil, ErrReservationConflict.wrap(fmt.Errorf("Lock file %v is held by another process", lock.path))
+	}
+
+	// move the stale lock out of the way under a name unique to this process, so only one process can take it over
+	stalePath := fmt.Sprintf("%v.%v.stale", lock.path, token)
+	err = os.Rename(lock.path, stalePath)
+	if err != nil {
+		return nil, ErrReservationConflict.wrap(fmt.Errorf("Lock file %v has been taken over by another process", lock.path))
+	}
+	defer os.Remove(stalePath)
+
+	// another process might have replaced the stale lock between checking and moving it, so check the moved lock file is still stale
+	info, statErr = os.Stat(stalePath)
+	if statErr != nil || time.Since(info.ModTime()) < b.lockTTL {
+		// put it back; if that fails its owner notices the lock is gone when confirming it
+		_ = os.Link(stalePath, lock.path)
+		return nil, ErrReservationConflict.wrap(fmt.Errorf("Lock file %v is held by another process", lock.path))
+	}
+
+	log.Warn().Msgf("Lock file %v is older than %v, taking it over", lock.path, b.lockTTL)
+
+	err = lock.create()
+	if err != nil && os.IsExist(err) {
+		return nil, ErrReservationConflict.wrap(fmt.Errorf("Lock file %v is held by another process", lock.path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return lock, nil
+}
+
+// create writes the lock file with the token, failing if the lock file already exists
+func (l *fileLock) create() (err error) {
+	lockFile, err := os.OpenFile(l.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	_, err = lockFile.WriteString(l.token)
+	closeErr := lockFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(l.path)
+	}
+
+	return
+}
+
+// confirm returns an error if the lock file no longer holds the token, because another process took it over
+func (l *fileLock) confirm() error {
+	data, err := ioutil.ReadFile(l.path)
+	if err != nil || string(data) != l.token {
+		return ErrReservationConflict.wrap(fmt.Errorf("Lock file %v has been taken over by another process", l.path))
+	}
+
+	return nil
+}
+
+// unlock removes the lock file, but only if it still holds the token
+func (l *fileLock) unlock() {
+	// move the lock file out of the way first, so checking the token and removing it can't remove a lock file another process just created
+	unlockPath := fmt.Sprintf("%v.%v.unlock", l.path, l.token)
+	err := os.Rename(l.path, unlockPath)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed removing lock file %v", l.path)
+		return
+	}
+	defer os.Remove(unlockPath)
+
+	data, err := ioutil.ReadFile(unlockPath)
+	if err != nil || string(data) != l.token {
+		log.Warn().Msgf("Lock file %v has been taken over by another process, leaving it", l.path)
+		_ = os.Link(unlockPath, l.path)
+	}
+}
+
+// newLockToken returns a random token identifying the owner of a lock file
+func newLockToken() (string, error) {
+	token := make([]byte, 16)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+func (b *fileLedgerBackend) getGeneration(data []byte) int64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return int64(h.Sum64() >> 1)
+}
@@ -0,0 +1,129 @@
+package planner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	foundation "github.com/estafette/estafette-foundation"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	storagev1 "google.golang.org/api/storage/v1"
+)
+
+// NewGCSReservationStore returns a ReservationStore keeping reservations in a gcs object; set endpoint to use a gcs compatible server like fake-gcs-server instead
+func NewGCSReservationStore(ctx context.Context, bucket, object, endpoint string) (ReservationStore, error) {
+
+	opts := []option.ClientOption{}
+	if endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint), option.WithoutAuthentication())
+	}
+
+	storagev1Service, err := storagev1.NewService(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledger{
+		backend: &gcsLedgerBackend{
+			storagev1Service: storagev1Service,
+			bucket:           bucket,
+			object:           object,
+		},
+	}, nil
+}
+
+type gcsLedgerBackend struct {
+	storagev1Service *storagev1.Service
+	bucket           string
+	object           string
+}
+
+func (b *gcsLedgerBackend) read(ctx context.Context) (data []byte, generation int64, err error) {
+	log.Debug().Msgf("Reading reservations from gs://%v/%v...", b.bucket, b.object)
+
+	err = foundation.Retry(func() error {
+		// download content and generation in a single request, so they can't belong to different versions of the object
+		resp, err := b.storagev1Service.Objects.Get(b.bucket, b.object).Context(ctx).Download()
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		generation, err = strconv.ParseInt(resp.Header.Get("X-Goog-Generation"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("Response has no valid X-Goog-Generation header: %w", err)
+		}
+
+		data, err = ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, b.getRetryOptions()...)
+
+	var googleapiErr *googleapi.Error
+	if err != nil && errors.As(err, &googleapiErr) && googleapiErr.Code == http.StatusNotFound {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("Can't read reservations from gs://%v/%v: %w", b.bucket, b.object, err)
+	}
+
+	return
+}
+
+func (b *gcsLedgerBackend) write(ctx context.Context, data []byte, generation int64) (err error) {
+	log.Debug().Msgf("Writing reservations to gs://%v/%v...", b.bucket, b.object)
+
+	err = foundation.Retry(func() error {
+		// generation 0 makes the write fail if the object has been created in the meantime
+		_, err := b.storagev1Service.Objects.Insert(b.bucket, &storagev1.Object{
+			Name:        b.object,
+			ContentType: "application/json",
+		}).IfGenerationMatch(generation).Media(bytes.NewReader(data)).Context(ctx).Do()
+		return err
+	}, b.getRetryOptions()...)
+
+	var googleapiErr *googleapi.Error
+	if err != nil && errors.As(err, &googleapiErr) && googleapiErr.Code == http.StatusPreconditionFailed {
+		// an earlier attempt, retried here or by the storage client itself, can have been applied even though its response was an error, in which case the retry fails on its own write
+		current, _, readErr := b.read(ctx)
+		if readErr == nil && bytes.Equal(current, data) {
+			log.Debug().Msgf("Write to gs://%v/%v failed its precondition, but an earlier attempt has been applied", b.bucket, b.object)
+			return nil
+		}
+
+		return ErrReservationConflict.wrap(fmt.Errorf("Object gs://%v/%v has been modified since generation %v was read", b.bucket, b.object, generation))
+	}
+	if err != nil {
+		return fmt.Errorf("Can't write reservations to gs://%v/%v: %w", b.bucket, b.object, err)
+	}
+
+	return
+}
+
+func (b *gcsLedgerBackend) getRetryOptions() []foundation.RetryOption {
+	return []foundation.RetryOption{
+		func(c *foundation.RetryConfig) {
+			c.IsRetryableError = func(err error) bool {
+				var googleapiErr *googleapi.Error
+				if errors.As(err, &googleapiErr) {
+					// Retry on 429 and 5xx, according to
+					// https://cloud.google.com/storage/docs/exponential-backoff.
+					return googleapiErr.Code == http.StatusTooManyRequests || (googleapiErr.Code >= 500 && googleapiErr.Code < 600)
+				}
+				return false
+			}
+		},
+		foundation.LastErrorOnly(true),
+		foundation.Attempts(5),
+		foundation.DelayMillisecond(1000),
+	}
+}
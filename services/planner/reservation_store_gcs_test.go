@@ -0,0 +1,268 @@
+package planner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGCSReservationStore(t *testing.T) {
+
+	t.Run("ReturnsNoReservationsWhenObjectDoesNotExist", func(t *testing.T) {
+
+		ctx := context.Background()
+		server := newFakeGCSServer()
+		defer server.Close()
+		store, err := NewGCSReservationStore(ctx, "bucket", "path/reservations.json", server.URL+"/storage/v1/")
+		assert.Nil(t, err)
+
+		// act
+		reservations, err := store.ListReservations(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(reservations))
+	})
+
+	t.Run("ReturnsReservedRanges", func(t *testing.T) {
+
+		ctx := context.Background()
+		server := newFakeGCSServer()
+		defer server.Close()
+		store, err := NewGCSReservationStore(ctx, "bucket", "path/reservations.json", server.URL+"/storage/v1/")
+		assert.Nil(t, err)
+
+		err = store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Owner: "alice"})
+		assert.Nil(t, err)
+
+		// act
+		reservations, err := store.ListReservations(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(reservations))
+		assert.Equal(t, "10.0.0.0/16", reservations[0].CIDR)
+		assert.Contains(t, string(server.objects["bucket/path/reservations.json"]), `"cidr": "10.0.0.0/16"`)
+	})
+
+	t.Run("ReturnsErrorWhenReservingRangeOverlappingWithActiveReservation", func(t *testing.T) {
+
+		ctx := context.Background()
+		server := newFakeGCSServer()
+		defer server.Close()
+		store, err := NewGCSReservationStore(ctx, "bucket", "reservations.json", server.URL+"/storage/v1/")
+		assert.Nil(t, err)
+
+		err = store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Owner: "alice"})
+		assert.Nil(t, err)
+
+		// act
+		err = store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/20", Owner: "bob"})
+
+		assert.NotNil(t, err)
+		assert.True(t, errors.Is(err, ErrRangeAlreadyReserved))
+		assert.Equal(t, "The range is already reserved: Range 10.0.0.0/20 overlaps with range 10.0.0.0/16 reserved by alice", err.Error())
+	})
+
+	t.Run("ReturnsConflictErrorWhenObjectHasBeenModifiedSinceItWasRead", func(t *testing.T) {
+
+		ctx := context.Background()
+		server := newFakeGCSServer()
+		defer server.Close()
+		store, err := NewGCSReservationStore(ctx, "bucket", "reservations.json", server.URL+"/storage/v1/")
+		assert.Nil(t, err)
+		backend := store.(*ledger).backend
+
+		_, generation, err := backend.read(ctx)
+		assert.Nil(t, err)
+		err = store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Owner: "alice"})
+		assert.Nil(t, err)
+
+		// act
+		err = backend.write(ctx, []byte(`{"reservations":[]}`), generation)
+
+		assert.NotNil(t, err)
+		assert.True(t, errors.Is(err, ErrReservationConflict))
+	})
+
+	t.Run("SucceedsWhenRetryFailsPreconditionBecauseFailedAttemptHasBeenApplied", func(t *testing.T) {
+
+		ctx := context.Background()
+		server := newFakeGCSServer()
+		defer server.Close()
+		server.failuresAfterWrite = 1
+		store, err := NewGCSReservationStore(ctx, "bucket", "reservations.json", server.URL+"/storage/v1/")
+		assert.Nil(t, err)
+
+		// act
+		err = store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Owner: "alice"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), server.generations["bucket/reservations.json"])
+		reservations, err := store.ListReservations(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(reservations))
+	})
+
+	t.Run("ReturnsConflictErrorWhenObjectHasBeenCreatedWithOtherContent", func(t *testing.T) {
+
+		ctx := context.Background()
+		server := newFakeGCSServer()
+		defer server.Close()
+		server.objects["bucket/reservations.json"] = []byte(`{"reservations":[{"cidr":"10.0.0.0/16"}]}`)
+		server.generations["bucket/reservations.json"] = 1
+		store, err := NewGCSReservationStore(ctx, "bucket", "reservations.json", server.URL+"/storage/v1/")
+		assert.Nil(t, err)
+		backend := store.(*ledger).backend
+
+		// act
+		err = backend.write(ctx, []byte(`{"reservations":[]}`), 0)
+
+		assert.NotNil(t, err)
+		assert.True(t, errors.Is(err, ErrReservationConflict))
+	})
+
+	t.Run("AllowsOnlyOneOfConcurrentOverlappingReservationsToSucceed", func(t *testing.T) {
+
+		ctx := context.Background()
+		server := newFakeGCSServer()
+		defer server.Close()
+
+		errs := reserveConcurrently(t, 10, func() ReservationStore {
+			store, err := NewGCSReservationStore(ctx, "bucket", "reservations.json", server.URL+"/storage/v1/")
+			assert.Nil(t, err)
+			return store
+		})
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			} else {
+				assert.True(t, errors.Is(err, ErrReservationConflict) || errors.Is(err, ErrRangeAlreadyReserved))
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+	})
+}
+
+// fakeGCSServer implements the parts of the gcs json api used by the gcs reservation store
+type fakeGCSServer struct {
+	*httptest.Server
+
+	mutex       sync.Mutex
+	objects     map[string][]byte
+	generations map[string]int64
+
+	// failuresAfterWrite is the number of uploads that are applied but answered with a 503
+	failuresAfterWrite int
+}
+
+func newFakeGCSServer() *fakeGCSServer {
+	f := &fakeGCSServer{
+		objects:     map[string][]byte{},
+		generations: map[string]int64{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+
+	return f
+}
+
+func (f *fakeGCSServer) handle(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/storage/v1/b/"):
+		// /storage/v1/b/{bucket}/o/{object}
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/storage/v1/b/"), "/o/", 2)
+		key := parts[0] + "/" + parts[1]
+		data, ok := f.objects[key]
+		if !ok {
+			f.writeError(w, http.StatusNotFound, "No such object")
+			return
+		}
+		generation := strconv.FormatInt(f.generations[key], 10)
+		if g := r.URL.Query().Get("generation"); g != "" && g != generation {
+			f.writeError(w, http.StatusNotFound, "No such object generation")
+			return
+		}
+		if r.URL.Query().Get("alt") == "media" {
+			w.Header().Set("X-Goog-Generation", generation)
+			w.Write(data)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"bucket": parts[0], "name": parts[1], "generation": generation})
+
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/"):
+		// /upload/storage/v1/b/{bucket}/o with multipart body containing metadata and media
+		bucket := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/upload/storage/v1/b/"), "/o")
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			f.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		reader := multipart.NewReader(r.Body, params["boundary"])
+
+		var metadata struct {
+			Name string `json:"name"`
+		}
+		metadataPart, err := reader.NextPart()
+		if err != nil {
+			f.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		err = json.NewDecoder(metadataPart).Decode(&metadata)
+		if err != nil {
+			f.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mediaPart, err := reader.NextPart()
+		if err != nil {
+			f.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		data, err := ioutil.ReadAll(mediaPart)
+		if err != nil {
+			f.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		key := bucket + "/" + metadata.Name
+		if g := r.URL.Query().Get("ifGenerationMatch"); g != "" && g != strconv.FormatInt(f.generations[key], 10) {
+			f.writeError(w, http.StatusPreconditionFailed, "Precondition Failed")
+			return
+		}
+
+		f.objects[key] = data
+		f.generations[key]++
+
+		// apply the write but respond as if it failed, like gcs can do on a timeout
+		if f.failuresAfterWrite > 0 {
+			f.failuresAfterWrite--
+			f.writeError(w, http.StatusServiceUnavailable, "Service Unavailable")
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"bucket": bucket, "name": metadata.Name, "generation": strconv.FormatInt(f.generations[key], 10)})
+
+	default:
+		f.writeError(w, http.StatusNotImplemented, "Not implemented by fake")
+	}
+}
+
+func (f *fakeGCSServer) writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]interface{}{"code": code, "message": message}})
+}
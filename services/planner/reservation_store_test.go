@@ -0,0 +1,334 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReservationStore(t *testing.T) {
+
+	t.Run("ReturnsFileReservationStoreForPath", func(t *testing.T) {
+
+		ctx := context.Background()
+
+		// act
+		store, err := NewReservationStore(ctx, "./reservations.json", "")
+
+		assert.Nil(t, err)
+		assert.IsType(t, &fileLedgerBackend{}, store.(*ledger).backend)
+	})
+
+	t.Run("ReturnsGCSReservationStoreForGSLocation", func(t *testing.T) {
+
+		ctx := context.Background()
+
+		// act
+		store, err := NewReservationStore(ctx, "gs://bucket/path/reservations.json", "http://localhost:4443/storage/v1/")
+
+		assert.Nil(t, err)
+		assert.IsType(t, &gcsLedgerBackend{}, store.(*ledger).backend)
+		assert.Equal(t, "bucket", store.(*ledger).backend.(*gcsLedgerBackend).bucket)
+		assert.Equal(t, "path/reservations.json", store.(*ledger).backend.(*gcsLedgerBackend).object)
+	})
+
+	t.Run("ReturnsErrorForGSLocationWithoutObject", func(t *testing.T) {
+
+		ctx := context.Background()
+
+		// act
+		_, err := NewReservationStore(ctx, "gs://bucket", "")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Location gs://bucket for reservations is invalid; please use gs://bucket/object", err.Error())
+	})
+}
+
+func TestFileReservationStore(t *testing.T) {
+
+	t.Run("ReturnsNoReservationsWhenFileDoesNotExist", func(t *testing.T) {
+
+		ctx := context.Background()
+		store := NewFileReservationStore(filepath.Join(getTempDir(t), "reservations.json"))
+
+		// act
+		reservations, err := store.ListReservations(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(reservations))
+	})
+
+	t.Run("ReturnsReservedRanges", func(t *testing.T) {
+
+		ctx := context.Background()
+		store := NewFileReservationStore(filepath.Join(getTempDir(t), "reservations.json"))
+
+		err := store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Type: networkv1.TypePod, Owner: "alice"})
+		assert.Nil(t, err)
+
+		// act
+		reservations, err := store.ListReservations(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(reservations))
+		assert.Equal(t, "10.0.0.0/16", reservations[0].CIDR)
+		assert.Equal(t, "alice", reservations[0].Owner)
+		assert.False(t, reservations[0].ReservedAt.IsZero())
+	})
+
+	t.Run("NormalizesReservedRange", func(t *testing.T) {
+
+		ctx := context.Background()
+		store := NewFileReservationStore(filepath.Join(getTempDir(t), "reservations.json"))
+
+		err := store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.12.1/16"})
+		assert.Nil(t, err)
+
+		// act
+		reservations, err := store.ListReservations(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(reservations))
+		assert.Equal(t, "10.0.0.0/16", reservations[0].CIDR)
+	})
+
+	t.Run("ReturnsErrorWhenReservingRangeOverlappingWithActiveReservation", func(t *testing.T) {
+
+		ctx := context.Background()
+		store := NewFileReservationStore(filepath.Join(getTempDir(t), "reservations.json"))
+
+		err := store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Owner: "alice"})
+		assert.Nil(t, err)
+
+		// act
+		err = store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.128.0/17", Owner: "bob"})
+
+		assert.NotNil(t, err)
+		assert.True(t, errors.Is(err, ErrRangeAlreadyReserved))
+		assert.Equal(t, "The range is already reserved: Range 10.0.128.0/17 overlaps with range 10.0.0.0/16 reserved by alice", err.Error())
+	})
+
+	t.Run("AllowsReservingRangeOverlappingWithExpiredReservation", func(t *testing.T) {
+
+		ctx := context.Background()
+		store := NewFileReservationStore(filepath.Join(getTempDir(t), "reservations.json"))
+
+		expiresAt := time.Now().UTC().Add(-time.Minute)
+		err := store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Owner: "alice", ExpiresAt: &expiresAt})
+		assert.Nil(t, err)
+
+		// act
+		err = store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Owner: "bob"})
+
+		assert.Nil(t, err)
+		reservations, err := store.ListReservations(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(reservations))
+		assert.Equal(t, "bob", reservations[0].Owner)
+	})
+
+	t.Run("RemovesReleasedRanges", func(t *testing.T) {
+
+		ctx := context.Background()
+		store := NewFileReservationStore(filepath.Join(getTempDir(t), "reservations.json"))
+
+		err := store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16"}, networkv1.Reservation{CIDR: "10.1.0.0/16"})
+		assert.Nil(t, err)
+
+		// act
+		err = store.Release(ctx, "10.0.0.0/16")
+
+		assert.Nil(t, err)
+		reservations, err := store.ListReservations(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(reservations))
+		assert.Equal(t, "10.1.0.0/16", reservations[0].CIDR)
+	})
+
+	t.Run("ReturnsErrorWhenReleasingRangeThatIsNotReserved", func(t *testing.T) {
+
+		ctx := context.Background()
+		store := NewFileReservationStore(filepath.Join(getTempDir(t), "reservations.json"))
+
+		// act
+		err := store.Release(ctx, "10.0.0.0/16")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Range 10.0.0.0/16 is not reserved", err.Error())
+	})
+
+	t.Run("ReturnsConflictErrorWhenFileHasBeenModifiedSinceItWasRead", func(t *testing.T) {
+
+		ctx := context.Background()
+		store := NewFileReservationStore(filepath.Join(getTempDir(t), "reservations.json"))
+		backend := store.(*ledger).backend
+
+		_, generation, err := backend.read(ctx)
+		assert.Nil(t, err)
+		err = store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Owner: "alice"})
+		assert.Nil(t, err)
+
+		// act
+		err = backend.write(ctx, []byte(`{"reservations":[]}`), generation)
+
+		assert.NotNil(t, err)
+		assert.True(t, errors.Is(err, ErrReservationConflict))
+	})
+
+	t.Run("ReturnsConflictErrorWhenLockFileIsHeld", func(t *testing.T) {
+
+		ctx := context.Background()
+		path := filepath.Join(getTempDir(t), "reservations.json")
+		store := NewFileReservationStore(path)
+		err := ioutil.WriteFile(path+".lock", []byte{}, 0644)
+		assert.Nil(t, err)
+
+		// act
+		err = store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Owner: "alice"})
+
+		assert.NotNil(t, err)
+		assert.True(t, errors.Is(err, ErrReservationConflict))
+	})
+
+	t.Run("TakesOverLockFileOlderThanTTL", func(t *testing.T) {
+
+		ctx := context.Background()
+		path := filepath.Join(getTempDir(t), "reservations.json")
+		store := NewFileReservationStore(path)
+		err := ioutil.WriteFile(path+".lock", []byte{}, 0644)
+		assert.Nil(t, err)
+		staleTime := time.Now().Add(-2 * defaultLockTTL)
+		err = os.Chtimes(path+".lock", staleTime, staleTime)
+		assert.Nil(t, err)
+
+		// act
+		err = store.Reserve(ctx, networkv1.Reservation{CIDR: "10.0.0.0/16", Owner: "alice"})
+
+		assert.Nil(t, err)
+		_, err = os.Stat(path + ".lock")
+		assert.True(t, os.IsNotExist(err))
+		files, err := ioutil.ReadDir(filepath.Dir(path))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(files))
+	})
+
+	t.Run("ReturnsConflictErrorWhenLockHasBeenTakenOverBeforeWriting", func(t *testing.T) {
+
+		path := filepath.Join(getTempDir(t), "reservations.json")
+		backend := NewFileReservationStore(path).(*ledger).backend.(*fileLedgerBackend)
+		lock, err := backend.lock()
+		assert.Nil(t, err)
+		err = ioutil.WriteFile(path+".lock", []byte("token-of-another-process"), 0644)
+		assert.Nil(t, err)
+
+		// act
+		err = lock.confirm()
+
+		assert.NotNil(t, err)
+		assert.True(t, errors.Is(err, ErrReservationConflict))
+	})
+
+	t.Run("LeavesLockFileOfAnotherProcessWhenUnlocking", func(t *testing.T) {
+
+		path := filepath.Join(getTempDir(t), "reservations.json")
+		backend := NewFileReservationStore(path).(*ledger).backend.(*fileLedgerBackend)
+		lock, err := backend.lock()
+		assert.Nil(t, err)
+		err = ioutil.WriteFile(path+".lock", []byte("token-of-another-process"), 0644)
+		assert.Nil(t, err)
+
+		// act
+		lock.unlock()
+
+		data, err := ioutil.ReadFile(path + ".lock")
+		assert.Nil(t, err)
+		assert.Equal(t, "token-of-another-process", string(data))
+	})
+
+	t.Run("AllowsOnlyOneOfConcurrentTakeoversOfStaleLockToSucceed", func(t *testing.T) {
+
+		path := filepath.Join(getTempDir(t), "reservations.json")
+		err := ioutil.WriteFile(path+".lock", []byte("token-of-crashed-process"), 0644)
+		assert.Nil(t, err)
+		staleTime := time.Now().Add(-2 * defaultLockTTL)
+		err = os.Chtimes(path+".lock", staleTime, staleTime)
+		assert.Nil(t, err)
+
+		errs := reserveConcurrently(t, 10, func() ReservationStore {
+			return NewFileReservationStore(path)
+		})
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			} else {
+				assert.True(t, errors.Is(err, ErrReservationConflict) || errors.Is(err, ErrRangeAlreadyReserved))
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+
+		reservations, err := NewFileReservationStore(path).ListReservations(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(reservations))
+	})
+
+	t.Run("AllowsOnlyOneOfConcurrentOverlappingReservationsToSucceed", func(t *testing.T) {
+
+		path := filepath.Join(getTempDir(t), "reservations.json")
+
+		errs := reserveConcurrently(t, 10, func() ReservationStore {
+			return NewFileReservationStore(path)
+		})
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			} else {
+				assert.True(t, errors.Is(err, ErrReservationConflict) || errors.Is(err, ErrRangeAlreadyReserved))
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+	})
+}
+
+// reserveConcurrently reserves overlapping ranges from separate stores at the same time and returns the error for each of them
+func reserveConcurrently(t *testing.T, n int, newStore func() ReservationStore) (errs []error) {
+	ctx := context.Background()
+
+	errs = make([]error, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int, store ReservationStore) {
+			defer wg.Done()
+			<-start
+			errs[i] = store.Reserve(ctx, networkv1.Reservation{CIDR: fmt.Sprintf("10.0.%v.0/24", i), Owner: fmt.Sprintf("owner-%v", i)}, networkv1.Reservation{CIDR: "10.1.0.0/16", Owner: fmt.Sprintf("owner-%v", i)})
+		}(i, newStore())
+	}
+	close(start)
+	wg.Wait()
+
+	return
+}
+
+func getTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "reservations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return dir
+}
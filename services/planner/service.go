@@ -2,10 +2,11 @@ package planner
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"net"
+	"strings"
 
 	"github.com/apparentlymart/go-cidr/cidr"
 	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
@@ -17,223 +18,508 @@ import (
 //go:generate mockgen -package=planner -destination ./mock.go -source=service.go
 type Service interface {
 	LoadConfig(ctx context.Context) (config *networkv1.Config, err error)
-	Suggest(ctx context.Context, filter string, networkTypes ...networkv1.Type) (subnetsMap map[networkv1.Type]*net.IPNet, err error)
-	SuggestSingleNetworkRange(ctx context.Context, rangeConfigs []networkv1.RangeConfig, subnetworks []*computev1.Subnetwork, routes []*computev1.Route, networkType networkv1.Type) (subnetworkRange *net.IPNet, err error)
+	Suggest(ctx context.Context, filter ProjectFilter, selector networkv1.Selector, count int, size networkv1.SubnetSize, networkTypes ...networkv1.Type) (suggestions []networkv1.Suggestion, err error)
+	SuggestSingleNetworkRange(ctx context.Context, rangeConfigs []networkv1.RangeConfig, subnetworks []*computev1.Subnetwork, routes []*computev1.Route, usedRanges []UsedRange, networkType networkv1.Type, selector networkv1.Selector, size networkv1.SubnetSize) (subnetworkRange *net.IPNet, err error)
+	Explain(ctx context.Context, filter ProjectFilter, selector networkv1.Selector, networkTypes ...networkv1.Type) (explanations []Explanation, err error)
+	ExplainSingleNetworkRange(ctx context.Context, rangeConfigs []networkv1.RangeConfig, subnetworks []*computev1.Subnetwork, routes []*computev1.Route, usedRanges []UsedRange, networkType networkv1.Type, selector networkv1.Selector) (explanation Explanation, err error)
+	Usage(ctx context.Context, filter ProjectFilter) (usages []RangeConfigUsage, err error)
+	UsageSingleRangeConfig(ctx context.Context, rangeConfig networkv1.RangeConfig, subnetworks []*computev1.Subnetwork, routes []*computev1.Route, usedRanges []UsedRange) (usage RangeConfigUsage, err error)
+	Audit(ctx context.Context, filter ProjectFilter) (overlaps []RangeOverlap, err error)
+	AuditRanges(ctx context.Context, subnetworks []*computev1.Subnetwork, routes []*computev1.Route) (overlaps []RangeOverlap, err error)
+	ListReservations(ctx context.Context) (reservations []networkv1.Reservation, err error)
+	Reserve(ctx context.Context, reservations ...networkv1.Reservation) (err error)
+	Release(ctx context.Context, cidrs ...string) (err error)
 }
 
-func NewService(ctx context.Context, gcpClient gcp.Client, configPath string) (Service, error) {
-	return &service{
-		gcpClient:  gcpClient,
-		configPath: configPath,
-	}, nil
+// NewService returns a new planner.Service; reservationStore is optional and when nil reservations are not taken into account
+func NewService(ctx context.Context, gcpClient gcp.Client, reservationStore ReservationStore, configPath string, options ...ServiceOption) (Service, error) {
+	s := &service{
+		gcpClient:        gcpClient,
+		reservationStore: reservationStore,
+		configPath:       configPath,
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	return s, nil
+}
+
+// ServiceOption configures optional behaviour of the planner.Service
+type ServiceOption func(*service)
+
+// WithAllocationStrategy overrides the allocation_strategy of all range configs unless it's networkv1.AllocationStrategyDefault
+func WithAllocationStrategy(allocationStrategy networkv1.AllocationStrategy) ServiceOption {
+	return func(s *service) {
+		s.allocationStrategy = allocationStrategy
+	}
 }
 
 type service struct {
-	gcpClient  gcp.Client
-	configPath string
+	gcpClient          gcp.Client
+	reservationStore   ReservationStore
+	configPath         string
+	allocationStrategy networkv1.AllocationStrategy
 }
 
 func (s *service) LoadConfig(ctx context.Context) (config *networkv1.Config, err error) {
 
 	var data []byte
+	path := s.configPath
 
-	if s.configPath != "" {
-		log.Info().Msgf("Reading config from %v...", s.configPath)
-		data, err = ioutil.ReadFile(s.configPath)
+	if path != "" {
+		log.Info().Msgf("Reading config from %v...", path)
+		data, err = ioutil.ReadFile(path)
 		if err != nil {
 			return
 		}
 	} else {
 		log.Info().Msg("Reading config from embedded config.json file...")
-		data, err = networkv1.Asset("config.json")
+		path = "config.json"
+		data, err = networkv1.Asset(path)
 		if err != nil {
 			return
 		}
 	}
 
-	err = json.Unmarshal(data, &config)
+	return networkv1.ParseConfig(data, path)
+}
+
+func (s *service) Suggest(ctx context.Context, filter ProjectFilter, selector networkv1.Selector, count int, size networkv1.SubnetSize, networkTypes ...networkv1.Type) (suggestions []networkv1.Suggestion, err error) {
+
+	config, err := s.getValidConfig(ctx)
+	if err != nil {
+		return
+	}
+
+	// fail before retrieving anything from gcp if any of the types can't be suggested
+	networkTypes, err = s.getNetworkTypes(config, selector, size, networkTypes)
+	if err != nil {
+		return
+	}
+
+	subnetworks, routes, usedRanges, err := s.getUsedResources(ctx, filter)
 	if err != nil {
 		return
 	}
 
+	// suggest at least one range per network type
+	if count < 1 {
+		count = 1
+	}
+
+	// get suggested subnets in the order of the requested network types
+	suggestions = []networkv1.Suggestion{}
+	for _, t := range networkTypes {
+		rangeConfig, err := s.getRangeConfig(config.RangeConfigs, t, selector)
+		if err != nil {
+			return suggestions, err
+		}
+
+		for i := 0; i < count; i++ {
+			subnetRange, err := s.SuggestSingleNetworkRange(ctx, config.RangeConfigs, subnetworks, routes, usedRanges, t, selector, size)
+			if err != nil {
+				return suggestions, err
+			}
+
+			log.Debug().Msgf("%v - %v", t, subnetRange)
+
+			// mark suggested range as used so the next suggestion doesn't return the same range
+			usedRanges = append(usedRanges, UsedRange{
+				CIDR:   subnetRange.String(),
+				Source: UsedRangeSourceSuggestion,
+				Name:   fmt.Sprintf("%v-%v", t, i+1),
+			})
+
+			suggestions = append(suggestions, networkv1.Suggestion{
+				Type:        t,
+				RangeType:   rangeConfig.RangeType,
+				CIDR:        subnetRange.String(),
+				RangeConfig: rangeConfig,
+			})
+		}
+	}
+
 	return
 }
 
-func (s *service) Suggest(ctx context.Context, filter string, networkTypes ...networkv1.Type) (subnetsMap map[networkv1.Type]*net.IPNet, err error) {
+func (s *service) SuggestSingleNetworkRange(ctx context.Context, rangeConfigs []networkv1.RangeConfig, subnetworks []*computev1.Subnetwork, routes []*computev1.Route, usedRanges []UsedRange, networkType networkv1.Type, selector networkv1.Selector, size networkv1.SubnetSize) (subnetworkRange *net.IPNet, err error) {
+
+	log.Debug().Msgf("Suggesting subnetwork range for network type %v (with %v range configs and %v subnetworks and %v routes and %v used ranges)...", networkType, len(rangeConfigs), len(subnetworks), len(routes), len(usedRanges))
 
-	config, err := s.LoadConfig(ctx)
+	rangeConfig, err := s.getRangeConfig(rangeConfigs, networkType, selector)
 	if err != nil {
 		return
 	}
 
-	valid, _, errors := config.Validate()
-	if !valid {
-		return subnetsMap, fmt.Errorf("Config at path %v is not valid: %v", s.configPath, errors)
+	subnetMask, err := rangeConfig.GetSubnetMask(size)
+	if err != nil {
+		return
 	}
 
-	projects, err := s.gcpClient.GetProjectByLabels(ctx, []string{filter})
+	applicableUsedRanges, err := s.getApplicableUsedRanges(rangeConfig, subnetworks, routes, usedRanges)
 	if err != nil {
 		return
 	}
 
-	subnetworks, err := s.gcpClient.GetProjectSubnetworks(ctx, projects)
+	// index all used ranges once, so candidates overlapping them can be skipped without comparing each of them against every used range
+	index, err := newUsedRangeIndex(applicableUsedRanges)
 	if err != nil {
 		return
 	}
 
-	routes, err := s.gcpClient.GetProjectRoutes(ctx, projects)
+	_, networkIPnet, err := net.ParseCIDR(rangeConfig.NetworkCIDR)
 	if err != nil {
 		return
 	}
 
-	// set default network type
-	if len(networkTypes) == 0 {
-		networkTypes = []networkv1.Type{
-			networkv1.TypeNode,
-			networkv1.TypePod,
-			networkv1.TypeService,
-			networkv1.TypeMaster,
-			networkv1.TypeOther,
+	// get free subnetwork range from rangeconfig according to its allocation strategy
+	// candidates are aligned to their own size, so ranges of different sizes can share the network without overlapping
+	allocationStrategy := rangeConfig.GetAllocationStrategy(s.allocationStrategy)
+	allocator, err := getAllocator(allocationStrategy)
+	if err != nil {
+		return
+	}
+	subnetRange, position := allocator.freeSubnet(index, networkIPnet, subnetMask, func(candidate *net.IPNet, interval ipInterval) {
+		for _, ur := range interval.usedRanges {
+			log.Debug().Msgf("Range %v is already used by %v", candidate, ur.Describe())
 		}
+	})
+	if subnetRange != nil {
+		log.Debug().Msgf("%vth range %v of total range %v is available, suggesting it using %v allocation", position, subnetRange, rangeConfig.NetworkCIDR, allocationStrategy)
+		return subnetRange, nil
+	}
+
+	if subnetMask != rangeConfig.SubnetMask {
+		return subnetworkRange, fmt.Errorf("All of the possible /%v subnets of range %v are already in use", subnetMask, rangeConfig.NetworkCIDR)
+	}
+
+	// count candidates as big.Int, since an ipv6 network can hold more subnets than fit in an int
+	ones, _ := networkIPnet.Mask.Size()
+	total := new(big.Int).Lsh(big.NewInt(1), uint(subnetMask-ones))
+
+	return subnetworkRange, fmt.Errorf("All of the possible %v subnets of range %v are already in use", total, rangeConfig.NetworkCIDR)
+}
+
+func (s *service) ListReservations(ctx context.Context) (reservations []networkv1.Reservation, err error) {
+	if s.reservationStore == nil {
+		return reservations, fmt.Errorf("No reservation store has been configured")
+	}
+
+	return s.reservationStore.ListReservations(ctx)
+}
+
+func (s *service) Reserve(ctx context.Context, reservations ...networkv1.Reservation) (err error) {
+	if s.reservationStore == nil {
+		return fmt.Errorf("No reservation store has been configured")
+	}
+
+	err = s.reservationStore.Reserve(ctx, reservations...)
+	if err != nil {
+		return
+	}
+
+	for _, r := range reservations {
+		log.Info().Msgf("Reserved range %v for type %v by %v", r.CIDR, r.Type, r.Owner)
+	}
+
+	return
+}
+
+func (s *service) Release(ctx context.Context, cidrs ...string) (err error) {
+	if s.reservationStore == nil {
+		return fmt.Errorf("No reservation store has been configured")
+	}
+
+	err = s.reservationStore.Release(ctx, cidrs...)
+	if err != nil {
+		return
+	}
+
+	for _, c := range cidrs {
+		log.Info().Msgf("Released range %v", c)
+	}
+
+	return
+}
+
+// getValidConfig loads the config and returns an error if it isn't valid
+func (s *service) getValidConfig(ctx context.Context) (config *networkv1.Config, err error) {
+
+	config, err = s.LoadConfig(ctx)
+	if err != nil {
+		return
+	}
+
+	valid, warnings, errors := config.Validate()
+	for _, w := range warnings {
+		log.Warn().Msgf("Config at path %v: %v", s.configPath, w)
+	}
+	if !valid {
+		return config, fmt.Errorf("Config at path %v is not valid: %v", s.configPath, errors)
+	}
+
+	return
+}
+
+// getNetworkTypes defaults to all types declared in the config and checks a range config exists for each type that allows the requested size
+func (s *service) getNetworkTypes(config *networkv1.Config, selector networkv1.Selector, size networkv1.SubnetSize, networkTypes []networkv1.Type) ([]networkv1.Type, error) {
+
+	if len(networkTypes) == 0 {
+		networkTypes = config.GetTypes()
 	}
 
-	// get suggested subnets
-	subnetsMap = map[networkv1.Type]*net.IPNet{}
 	for _, t := range networkTypes {
-		subnetRange, err := s.SuggestSingleNetworkRange(ctx, config.RangeConfigs, subnetworks, routes, t)
+		if !config.IsDeclaredType(t) {
+			return networkTypes, fmt.Errorf("Type %v is not declared in the config; please use one of %v", t, s.describeTypes(config.GetTypes()))
+		}
+
+		rangeConfig, err := s.getRangeConfig(config.RangeConfigs, t, selector)
+		if err != nil {
+			return networkTypes, err
+		}
+
+		_, err = rangeConfig.GetSubnetMask(size)
 		if err != nil {
-			return subnetsMap, err
+			return networkTypes, err
 		}
-		subnetsMap[t] = subnetRange
 	}
 
-	// log suggested subnets:
-	for k, v := range subnetsMap {
-		log.Info().Msgf("%v - %v", k, v)
+	return networkTypes, nil
+}
+
+func (s *service) describeTypes(networkTypes []networkv1.Type) string {
+	names := []string{}
+	for _, t := range networkTypes {
+		names = append(names, string(t))
 	}
 
-	return
+	return strings.Join(names, ", ")
 }
 
-func (s *service) SuggestSingleNetworkRange(ctx context.Context, rangeConfigs []networkv1.RangeConfig, subnetworks []*computev1.Subnetwork, routes []*computev1.Route, networkType networkv1.Type) (subnetworkRange *net.IPNet, err error) {
+// getUsedResources retrieves everything that occupies network ranges for the projects matching the filter
+func (s *service) getUsedResources(ctx context.Context, filter ProjectFilter) (subnetworks []*computev1.Subnetwork, routes []*computev1.Route, usedRanges []UsedRange, err error) {
+
+	projects, err := s.getProjects(ctx, filter)
+	if err != nil {
+		return
+	}
+
+	subnetworks, err = s.gcpClient.GetProjectSubnetworks(ctx, projects)
+	if err != nil {
+		return
+	}
+	subnetworks = dedupeSubnetworks(subnetworks)
 
-	log.Debug().Msgf("Suggesting subnetwork range for network type %v (with %v range configs and %v subnetworks and %v routes)...", networkType, len(rangeConfigs), len(subnetworks), len(routes))
+	routes, err = s.gcpClient.GetProjectRoutes(ctx, projects)
+	if err != nil {
+		return
+	}
+	routes = dedupeRoutes(routes)
 
-	// find range config for region and network type
-	filteredRangeConfigs := []networkv1.RangeConfig{}
-	for _, rc := range rangeConfigs {
-		if rc.Type == networkType {
-			filteredRangeConfigs = append(filteredRangeConfigs, rc)
+	// treat ranges imported over vpc peering as used, since they're reachable from the networks even if the peer networks don't match the filter
+	networks, err := s.gcpClient.GetProjectNetworks(ctx, projects)
+	if err != nil {
+		return
+	}
+
+	peeringRoutes, err := s.gcpClient.GetNetworkPeeringRoutes(ctx, networks)
+	if err != nil {
+		return
+	}
+
+	usedRanges = []UsedRange{}
+	for _, r := range peeringRoutes {
+		// a peer exporting a default route would otherwise mark the entire address space as used
+		if isDefaultRoute(r.DestRange) {
+			continue
 		}
+		usedRanges = append(usedRanges, newPeeringRouteUsedRange(r))
 	}
 
-	if len(filteredRangeConfigs) == 0 {
-		return subnetworkRange, fmt.Errorf("No ranges have been configured for type %v, can't suggest a subnetwork range", networkType)
+	// treat internal addresses as used, like the ranges allocated to servicenetworking for private service access
+	addresses, err := s.gcpClient.GetProjectInternalAddresses(ctx, projects)
+	if err != nil {
+		return
+	}
+	for _, a := range addresses {
+		usedRanges = append(usedRanges, newAddressUsedRange(a))
 	}
 
-	if len(filteredRangeConfigs) > 1 {
-		return subnetworkRange, fmt.Errorf("Multiple ranges have been configured for type %v, can't suggest a subnetwork range", networkType)
+	// treat gke cluster ranges that don't live on a subnetwork as used
+	clusters, err := s.gcpClient.GetProjectClusters(ctx, projects)
+	if err != nil {
+		return
+	}
+	for _, c := range clusters {
+		usedRanges = append(usedRanges, newClusterUsedRanges(c)...)
+	}
+
+	// treat active reservations as used
+	if s.reservationStore != nil {
+		reservations, listErr := s.reservationStore.ListReservations(ctx)
+		if listErr != nil {
+			return subnetworks, routes, usedRanges, listErr
+		}
+		for _, r := range reservations {
+			usedRanges = append(usedRanges, UsedRange{
+				CIDR:   r.CIDR,
+				Source: UsedRangeSourceReservation,
+				Name:   fmt.Sprintf("reserved by %v", r.Owner),
+			})
+		}
+	}
+
+	return
+}
+
+// dedupeSubnetworks removes subnetworks with the same self link, which show up more than once when a shared vpc host also matches the filter
+func dedupeSubnetworks(subnetworks []*computev1.Subnetwork) []*computev1.Subnetwork {
+	selfLinks := map[string]bool{}
+	deduped := []*computev1.Subnetwork{}
+	for _, sn := range subnetworks {
+		if sn.SelfLink != "" && selfLinks[sn.SelfLink] {
+			continue
+		}
+		selfLinks[sn.SelfLink] = true
+		deduped = append(deduped, sn)
 	}
 
-	rangeConfig := filteredRangeConfigs[0]
+	return deduped
+}
+
+// dedupeRoutes removes routes with the same self link, which show up more than once when a shared vpc host also matches the filter
+func dedupeRoutes(routes []*computev1.Route) []*computev1.Route {
+	selfLinks := map[string]bool{}
+	deduped := []*computev1.Route{}
+	for _, r := range routes {
+		if r.SelfLink != "" && selfLinks[r.SelfLink] {
+			continue
+		}
+		selfLinks[r.SelfLink] = true
+		deduped = append(deduped, r)
+	}
+
+	return deduped
+}
+
+// getApplicableUsedRanges returns subnetwork ranges, routes and other used ranges overlapping with the range config network
+func (s *service) getApplicableUsedRanges(rangeConfig networkv1.RangeConfig, subnetworks []*computev1.Subnetwork, routes []*computev1.Route, usedRanges []UsedRange) (applicableUsedRanges []UsedRange, err error) {
+
+	applicableUsedRanges = []UsedRange{}
 
 	// filter subnetworks on whether they're contained in the range config network CIDR
-	filteredSubnetworkCIDRs := []string{}
+	filteredSubnetworkRanges := 0
 	for _, sn := range subnetworks {
+		candidates := []UsedRange{}
 		switch rangeConfig.RangeType {
 		case networkv1.RangeTypePrimary:
-			overlap, overlapErr := s.rangesOverlap(rangeConfig.NetworkCIDR, sn.IpCidrRange)
+			candidates = append(candidates, newSubnetworkUsedRange(sn, sn.IpCidrRange, UsedRangeSourceSubnetwork, ""))
+
+		case networkv1.RangeTypeSecondary:
+			for _, sr := range sn.SecondaryIpRanges {
+				candidates = append(candidates, newSubnetworkUsedRange(sn, sr.IpCidrRange, UsedRangeSourceSecondaryRange, sr.RangeName))
+			}
+		}
+
+		// the ipv6 range of a dual-stack subnetwork is used by both nodes and pods, so check it for either range type
+		if sn.Ipv6CidrRange != "" {
+			candidates = append(candidates, newSubnetworkUsedRange(sn, sn.Ipv6CidrRange, UsedRangeSourceSubnetwork, ""))
+		}
+
+		for _, c := range candidates {
+			overlap, overlapErr := rangesOverlap(rangeConfig.NetworkCIDR, c.CIDR)
 			if overlapErr != nil {
 				return nil, overlapErr
 			}
 			if overlap {
-				filteredSubnetworkCIDRs = append(filteredSubnetworkCIDRs, sn.IpCidrRange)
-			}
-
-		case networkv1.RangeTypeSecondary:
-			for _, sr := range sn.SecondaryIpRanges {
-				overlap, overlapErr := s.rangesOverlap(rangeConfig.NetworkCIDR, sr.IpCidrRange)
-				if overlapErr != nil {
-					return nil, overlapErr
-				}
-				if overlap {
-					filteredSubnetworkCIDRs = append(filteredSubnetworkCIDRs, sr.IpCidrRange)
-				}
+				applicableUsedRanges = append(applicableUsedRanges, c)
+				filteredSubnetworkRanges++
 			}
 		}
 	}
-	log.Debug().Msgf("Filtered subnetworks down to %v applicable subnetworks", len(filteredSubnetworkCIDRs))
+	log.Debug().Msgf("Filtered subnetworks down to %v applicable subnetworks", filteredSubnetworkRanges)
 
 	// filter routes on whether they're contained in the range config network CIDR
-	filteredRouteCIDRs := []string{}
+	filteredRoutes := 0
 	for _, r := range routes {
-		if r.DestRange == "0.0.0.0/0" {
+		if isDefaultRoute(r.DestRange) {
 			continue
 		}
 
-		overlap, overlapErr := s.rangesOverlap(rangeConfig.NetworkCIDR, r.DestRange)
+		overlap, overlapErr := rangesOverlap(rangeConfig.NetworkCIDR, r.DestRange)
 		if overlapErr != nil {
 			return nil, overlapErr
 		}
 		if overlap {
-			filteredRouteCIDRs = append(filteredRouteCIDRs, r.DestRange)
+			applicableUsedRanges = append(applicableUsedRanges, newRouteUsedRange(r))
+			filteredRoutes++
 		}
 	}
-	log.Debug().Msgf("Filtered routes down to %v applicable routes", len(filteredRouteCIDRs))
+	log.Debug().Msgf("Filtered routes down to %v applicable routes", filteredRoutes)
 
-	// get first free subnetwork range from rangeconfig
-	availableSubnetworkRanges := rangeConfig.GetAvailableSubnetworkRanges()
-	for i, subnetRange := range availableSubnetworkRanges {
-		// check if it's in use by any of the filtered subnets
-		rangeIsInUse := false
-		for _, snCIDR := range filteredSubnetworkCIDRs {
-			overlap, overlapErr := s.rangesOverlap(subnetRange.String(), snCIDR)
-			if overlapErr != nil {
-				return nil, overlapErr
-			}
-			if overlap {
-				log.Debug().Msgf("Range %v is already used by subnet with cidr %v", subnetRange, snCIDR)
-				rangeIsInUse = true
-				break
-			}
+	// filter used ranges on whether they're contained in the range config network CIDR
+	filteredUsedRanges := 0
+	for _, ur := range usedRanges {
+		overlap, overlapErr := rangesOverlap(rangeConfig.NetworkCIDR, ur.CIDR)
+		if overlapErr != nil {
+			return nil, overlapErr
 		}
-		if !rangeIsInUse {
-			// check if it's in use by any of the filtered routes
-			for _, rCIDR := range filteredRouteCIDRs {
-				overlap, overlapErr := s.rangesOverlap(subnetRange.String(), rCIDR)
-				if overlapErr != nil {
-					return nil, overlapErr
-				}
-				if overlap {
-					log.Debug().Msgf("Range %v is already used by route with cidr %v", subnetRange, rCIDR)
-					rangeIsInUse = true
-					break
-				}
-			}
+		if overlap {
+			applicableUsedRanges = append(applicableUsedRanges, ur)
+			filteredUsedRanges++
 		}
+	}
+	log.Debug().Msgf("Filtered used ranges down to %v applicable used ranges", filteredUsedRanges)
+
+	return
+}
+
+func (s *service) getRangeConfig(rangeConfigs []networkv1.RangeConfig, networkType networkv1.Type, selector networkv1.Selector) (rangeConfig networkv1.RangeConfig, err error) {
 
-		if !rangeIsInUse {
-			log.Debug().Msgf("%vth range %v of total range %v is available, suggesting it", i, subnetRange, rangeConfig.NetworkCIDR)
-			return subnetRange, nil
+	// find range config for region, environment, network and network type, keeping only the most specific ones
+	filteredRangeConfigs := []networkv1.RangeConfig{}
+	maxSpecificity := 0
+	for _, rc := range rangeConfigs {
+		if rc.Type != networkType || !rc.Selector.Matches(selector) {
+			continue
 		}
-	}
 
-	log.Debug().Msg("Showing subnetworks and routes to find conflicts")
+		specificity := rc.Selector.Specificity()
+		if specificity > maxSpecificity {
+			filteredRangeConfigs = []networkv1.RangeConfig{}
+			maxSpecificity = specificity
+		}
+		if specificity == maxSpecificity {
+			filteredRangeConfigs = append(filteredRangeConfigs, rc)
+		}
+	}
 
-	log.Debug().Msg("Subnetworks:")
-	for _, sn := range subnetworks {
-		log.Debug().Interface("subnetwork", sn).Msg("")
+	if len(filteredRangeConfigs) == 0 {
+		return rangeConfig, fmt.Errorf("No ranges have been configured for type %v%v, can't suggest a subnetwork range", networkType, s.describeSelector(selector))
 	}
 
-	log.Debug().Msg("Routes:")
-	for _, r := range routes {
-		log.Debug().Interface("route", r).Msg("")
+	if len(filteredRangeConfigs) > 1 {
+		return rangeConfig, fmt.Errorf("Multiple ranges have been configured for type %v%v, can't suggest a subnetwork range", networkType, s.describeSelector(selector))
 	}
 
-	return subnetworkRange, fmt.Errorf("All of the possible %v subnets of range %v are already in use", len(availableSubnetworkRanges), rangeConfig.NetworkCIDR)
+	return filteredRangeConfigs[0], nil
+}
+
+func (s *service) describeSelector(selector networkv1.Selector) (description string) {
+	if selector.Region != "" {
+		description += fmt.Sprintf(" in region %v", selector.Region)
+	}
+	if selector.Environment != "" {
+		description += fmt.Sprintf(" in environment %v", selector.Environment)
+	}
+	if selector.NetworkName != "" {
+		description += fmt.Sprintf(" in network %v", selector.NetworkName)
+	}
+	return
 }
 
-func (s *service) rangesOverlap(cidrA, cidrB string) (overlap bool, err error) {
+func rangesOverlap(cidrA, cidrB string) (overlap bool, err error) {
 
 	_, ipnetA, err := net.ParseCIDR(cidrA)
 	if err != nil {
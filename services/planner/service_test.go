@@ -2,6 +2,8 @@ package planner
 
 import (
 	"context"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
@@ -10,45 +12,1239 @@ import (
 	"github.com/stretchr/testify/assert"
 	crmv1 "google.golang.org/api/cloudresourcemanager/v1"
 	computev1 "google.golang.org/api/compute/v1"
+	containerv1 "google.golang.org/api/container/v1"
 )
 
+func TestLoadConfig(t *testing.T) {
+
+	t.Run("ReturnsEmbeddedConfigIfConfigPathIsEmpty", func(t *testing.T) {
+
+		ctx := context.Background()
+		service, err := NewService(ctx, nil, nil, "")
+
+		// act
+		config, err := service.LoadConfig(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, networkv1.APIVersion, config.APIVersion)
+		assert.Equal(t, 5, len(config.RangeConfigs))
+	})
+
+	t.Run("ReturnsConfigFromYAMLFile", func(t *testing.T) {
+
+		path := filepath.Join(getTempDir(t), "config.yaml")
+		err := ioutil.WriteFile(path, []byte("apiVersion: network/v1\nkind: Config\nrange_configs:\n- type: node\n  ip_cidr_range_type: primary\n  network: 172.28.0.0/14\n  subnet_mask: 21\n"), 0644)
+		assert.Nil(t, err)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, nil, nil, path)
+
+		// act
+		config, err := service.LoadConfig(ctx)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(config.RangeConfigs))
+		assert.Equal(t, "172.28.0.0/14", config.RangeConfigs[0].NetworkCIDR)
+	})
+}
+
 func TestSuggest(t *testing.T) {
 
-	t.Run("ReturnsSuggestionsForNodePodAndServiceRanges", func(t *testing.T) {
+	t.Run("ReturnsSuggestionsForNodePodAndServiceRanges", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Subnetwork{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Network{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Any()).
+			Return([]*gcp.PeeringRoute{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Address{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(projects)).
+			Return([]*containerv1.Cluster{}, nil)
+
+		// act
+		_, err = service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("ReturnsSuggestionsForTypesDeclaredInConfig", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		path := filepath.Join(getTempDir(t), "config.yaml")
+		err := ioutil.WriteFile(path, []byte(`apiVersion: network/v1
+kind: Config
+types:
+- node
+- psa
+range_configs:
+- type: node
+  ip_cidr_range_type: primary
+  network: 172.28.0.0/14
+  subnet_mask: 21
+- type: psa
+  ip_cidr_range_type: primary
+  network: 192.168.0.0/16
+  subnet_mask: 20
+`), 0644)
+		assert.Nil(t, err)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, path)
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Subnetwork{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Network{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Any()).
+			Return([]*gcp.PeeringRoute{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Address{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(projects)).
+			Return([]*containerv1.Cluster{}, nil)
+
+		// act
+		suggestions, err := service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(suggestions))
+		assert.Equal(t, networkv1.TypeNode, suggestions[0].Type)
+		assert.Equal(t, networkv1.Type("psa"), suggestions[1].Type)
+		assert.Equal(t, "192.168.0.0/20", suggestions[1].CIDR)
+	})
+
+	t.Run("ReturnsErrorWithoutCallingGCPIfRequestedTypeHasNoRangeConfig", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		path := filepath.Join(getTempDir(t), "config.json")
+		err := ioutil.WriteFile(path, []byte(`{"range_configs": [{"type": "node", "ip_cidr_range_type": "primary", "network": "172.28.0.0/14", "subnet_mask": 21}]}`), 0644)
+		assert.Nil(t, err)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, path)
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		// act
+		_, err = service.Suggest(ctx, filter, networkv1.Selector{Region: "europe-west1"}, 1, networkv1.SubnetSize{}, networkv1.TypeNode, networkv1.TypeService)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "No ranges have been configured for type service in region europe-west1, can't suggest a subnetwork range", err.Error())
+	})
+
+	t.Run("ReturnsErrorWithoutCallingGCPIfRequestedTypeIsNotDeclared", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		// act
+		_, err = service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{}, networkv1.TypeNode, networkv1.Type("psa"))
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Type psa is not declared in the config; please use one of node, pod, service, master, other", err.Error())
+	})
+
+	t.Run("ReturnsSuggestionOfRequestedSizeForSingleTypeIfOtherTypesDoNotAllowSize", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		path := filepath.Join(getTempDir(t), "config.yaml")
+		err := ioutil.WriteFile(path, []byte(`apiVersion: network/v1
+kind: Config
+range_configs:
+- type: node
+  ip_cidr_range_type: primary
+  network: 172.28.0.0/14
+  subnet_mask: 21
+  min_subnet_mask: 19
+- type: pod
+  ip_cidr_range_type: secondary
+  network: 10.0.0.0/9
+  subnet_mask: 16
+`), 0644)
+		assert.Nil(t, err)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, path)
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Subnetwork{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Network{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Any()).
+			Return([]*gcp.PeeringRoute{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Address{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(projects)).
+			Return([]*containerv1.Cluster{}, nil)
+
+		// act
+		suggestions, err := service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{PrefixLength: 20}, networkv1.TypeNode)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(suggestions))
+		assert.Equal(t, networkv1.TypeNode, suggestions[0].Type)
+		assert.Equal(t, "172.28.0.0/20", suggestions[0].CIDR)
+	})
+
+	t.Run("ReturnsErrorWithoutCallingGCPIfRequestedSizeIsNotAllowedForAllTypes", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		path := filepath.Join(getTempDir(t), "config.yaml")
+		err := ioutil.WriteFile(path, []byte(`apiVersion: network/v1
+kind: Config
+range_configs:
+- type: node
+  ip_cidr_range_type: primary
+  network: 172.28.0.0/14
+  subnet_mask: 21
+  min_subnet_mask: 19
+- type: pod
+  ip_cidr_range_type: secondary
+  network: 10.0.0.0/9
+  subnet_mask: 16
+`), 0644)
+		assert.Nil(t, err)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, path)
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		// act
+		_, err = service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{PrefixLength: 20})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "Requested size /20 for type pod is outside of the sizes /16 to /16 allowed for range 10.0.0.0/9", err.Error())
+	})
+
+	t.Run("SkipsRangesUsedByClusterMastersRoutesBasedClustersAndTPUs", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Subnetwork{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Network{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Any()).
+			Return([]*gcp.PeeringRoute{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Address{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(projects)).
+			Return([]*containerv1.Cluster{
+				{
+					Name:     "private-cluster",
+					Location: "europe-west4-a",
+					PrivateClusterConfig: &containerv1.PrivateClusterConfig{
+						MasterIpv4CidrBlock: "192.168.0.0/28",
+					},
+					IpAllocationPolicy: &containerv1.IPAllocationPolicy{
+						UseIpAliases: true,
+					},
+					TpuIpv4CidrBlock: "172.28.0.0/20",
+				},
+				{
+					Name:             "legacy-cluster",
+					Location:         "europe-west4",
+					ClusterIpv4Cidr:  "10.0.0.0/14",
+					ServicesIpv4Cidr: "172.24.0.0/20",
+				},
+			}, nil)
+
+		// act
+		suggestions, err := service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{}, networkv1.TypeNode, networkv1.TypePod, networkv1.TypeService, networkv1.TypeMaster)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 4, len(suggestions))
+		assert.Equal(t, "172.28.16.0/21", suggestions[0].CIDR)
+		assert.Equal(t, "10.4.0.0/16", suggestions[1].CIDR)
+		assert.Equal(t, "172.24.16.0/22", suggestions[2].CIDR)
+		assert.Equal(t, "192.168.0.16/28", suggestions[3].CIDR)
+	})
+
+	t.Run("ReturnsSuggestionsInOrderOfRequestedNetworkTypes", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Subnetwork{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Network{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Any()).
+			Return([]*gcp.PeeringRoute{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Address{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(projects)).
+			Return([]*containerv1.Cluster{}, nil)
+
+		// act
+		suggestions, err := service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{}, networkv1.TypeService, networkv1.TypeNode)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(suggestions))
+		assert.Equal(t, networkv1.TypeService, suggestions[0].Type)
+		assert.Equal(t, networkv1.RangeTypeSecondary, suggestions[0].RangeType)
+		assert.Equal(t, "172.24.0.0/22", suggestions[0].CIDR)
+		assert.Equal(t, "172.24.0.0/14", suggestions[0].RangeConfig.NetworkCIDR)
+		assert.Equal(t, networkv1.TypeNode, suggestions[1].Type)
+		assert.Equal(t, networkv1.RangeTypePrimary, suggestions[1].RangeType)
+		assert.Equal(t, "172.28.0.0/21", suggestions[1].CIDR)
+		assert.Equal(t, "Every subnet has four reserved IP addresses in its primary IP range", suggestions[1].RangeConfig.Comment)
+	})
+
+	t.Run("ReturnsDistinctSuggestionsWhenCountIsMoreThanOne", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Subnetwork{
+				{
+					IpCidrRange: "172.28.8.0/21",
+				},
+			}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Network{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Any()).
+			Return([]*gcp.PeeringRoute{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Address{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(projects)).
+			Return([]*containerv1.Cluster{}, nil)
+
+		// act
+		suggestions, err := service.Suggest(ctx, filter, networkv1.Selector{}, 3, networkv1.SubnetSize{}, networkv1.TypeNode)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(suggestions))
+		assert.Equal(t, "172.28.0.0/21", suggestions[0].CIDR)
+		assert.Equal(t, "172.28.16.0/21", suggestions[1].CIDR)
+		assert.Equal(t, "172.28.24.0/21", suggestions[2].CIDR)
+	})
+
+	t.Run("ReturnsSuggestionsNotOverlappingWithActiveReservations", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		reservationStore := NewFileReservationStore(filepath.Join(getTempDir(t), "reservations.json"))
+		err := reservationStore.Reserve(ctx, networkv1.Reservation{CIDR: "172.28.0.0/21", Owner: "alice"})
+		assert.Nil(t, err)
+		service, err := NewService(ctx, gcpClientMock, reservationStore, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Subnetwork{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Network{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Any()).
+			Return([]*gcp.PeeringRoute{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Address{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(projects)).
+			Return([]*containerv1.Cluster{}, nil)
+
+		// act
+		suggestions, err := service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{}, networkv1.TypeNode)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(suggestions))
+		assert.Equal(t, "172.28.8.0/21", suggestions[0].CIDR)
+	})
+
+	t.Run("ReturnsSuggestionsNotOverlappingWithRangesImportedOverPeering", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+		networks := []*computev1.Network{
+			{
+				Name:     "my-vpc",
+				SelfLink: "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/my-vpc",
+				Peerings: []*computev1.NetworkPeering{
+					{
+						Name:    "servicenetworking-googleapis-com",
+						Network: "https://www.googleapis.com/compute/v1/projects/tenant-project/global/networks/servicenetworking",
+						State:   "ACTIVE",
+					},
+				},
+			},
+		}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Subnetwork{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(projects)).
+			Return(networks, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Eq(networks)).
+			Return([]*gcp.PeeringRoute{
+				{
+					ExchangedPeeringRoute: &computev1.ExchangedPeeringRoute{
+						DestRange: "172.28.0.0/20",
+						Imported:  true,
+						Type:      "SUBNET_PEERING_ROUTE",
+					},
+					Network:     networks[0].SelfLink,
+					PeeringName: networks[0].Peerings[0].Name,
+					PeerNetwork: networks[0].Peerings[0].Network,
+				},
+			}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Address{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(projects)).
+			Return([]*containerv1.Cluster{}, nil)
+
+		// act
+		suggestions, err := service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{}, networkv1.TypeNode)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(suggestions))
+		assert.Equal(t, "172.28.16.0/21", suggestions[0].CIDR)
+	})
+
+	t.Run("IgnoresDefaultRoutesImportedOverPeering", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+		networks := []*computev1.Network{
+			{
+				Name:     "my-vpc",
+				SelfLink: "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/my-vpc",
+				Peerings: []*computev1.NetworkPeering{
+					{
+						Name:    "servicenetworking-googleapis-com",
+						Network: "https://www.googleapis.com/compute/v1/projects/tenant-project/global/networks/servicenetworking",
+						State:   "ACTIVE",
+					},
+				},
+			},
+		}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Subnetwork{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(projects)).
+			Return(networks, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Eq(networks)).
+			Return([]*gcp.PeeringRoute{
+				{
+					ExchangedPeeringRoute: &computev1.ExchangedPeeringRoute{
+						DestRange: "0.0.0.0/0",
+						Imported:  true,
+						Type:      "STATIC_PEERING_ROUTE",
+					},
+					Network:     networks[0].SelfLink,
+					PeeringName: networks[0].Peerings[0].Name,
+					PeerNetwork: networks[0].Peerings[0].Network,
+				},
+				{
+					ExchangedPeeringRoute: &computev1.ExchangedPeeringRoute{
+						DestRange: "::/0",
+						Imported:  true,
+						Type:      "STATIC_PEERING_ROUTE",
+					},
+					Network:     networks[0].SelfLink,
+					PeeringName: networks[0].Peerings[0].Name,
+					PeerNetwork: networks[0].Peerings[0].Network,
+				},
+				{
+					ExchangedPeeringRoute: &computev1.ExchangedPeeringRoute{
+						DestRange: "172.28.0.0/20",
+						Imported:  true,
+						Type:      "SUBNET_PEERING_ROUTE",
+					},
+					Network:     networks[0].SelfLink,
+					PeeringName: networks[0].Peerings[0].Name,
+					PeerNetwork: networks[0].Peerings[0].Network,
+				},
+			}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Address{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(projects)).
+			Return([]*containerv1.Cluster{}, nil)
+
+		// act
+		suggestions, err := service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{}, networkv1.TypeNode, networkv1.TypePod)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(suggestions))
+		assert.Equal(t, "172.28.16.0/21", suggestions[0].CIDR)
+		assert.Equal(t, "10.0.0.0/16", suggestions[1].CIDR)
+	})
+
+	t.Run("ReturnsSuggestionsNotOverlappingWithSubnetworksOfSharedVPCHostProjects", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{{ProjectId: "service-project"}}
+		hostProjects := []*crmv1.Project{{ProjectId: "host-project"}}
+		allProjects := []*crmv1.Project{projects[0], hostProjects[0]}
+		hostSubnetwork := &computev1.Subnetwork{
+			Name:        "shared",
+			IpCidrRange: "172.28.0.0/21",
+			SelfLink:    "https://www.googleapis.com/compute/v1/projects/host-project/regions/europe-west4/subnetworks/shared",
+		}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return(hostProjects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(allProjects)).
+			Return([]*computev1.Subnetwork{hostSubnetwork, hostSubnetwork}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(allProjects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(allProjects)).
+			Return([]*computev1.Network{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Any()).
+			Return([]*gcp.PeeringRoute{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(allProjects)).
+			Return([]*computev1.Address{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(allProjects)).
+			Return([]*containerv1.Cluster{}, nil)
+
+		// act
+		suggestions, err := service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{}, networkv1.TypeNode)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(suggestions))
+		assert.Equal(t, "172.28.8.0/21", suggestions[0].CIDR)
+	})
+
+	t.Run("ReturnsSuggestionsNotOverlappingWithInternalAddresses", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+		filter := ProjectFilter{Filter: "labels.environment=dev"}
+
+		projects := []*crmv1.Project{}
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectByLabels(gomock.Any(), gomock.Any()).
+			Return(projects, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetSharedVPCHostProjects(gomock.Any(), gomock.Eq(projects)).
+			Return([]*crmv1.Project{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Subnetwork{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Route{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectNetworks(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Network{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetNetworkPeeringRoutes(gomock.Any(), gomock.Any()).
+			Return([]*gcp.PeeringRoute{}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectInternalAddresses(gomock.Any(), gomock.Eq(projects)).
+			Return([]*computev1.Address{
+				{
+					Name:         "google-managed-services-my-vpc",
+					Address:      "172.28.0.0",
+					PrefixLength: 19,
+					Purpose:      "VPC_PEERING",
+					AddressType:  "INTERNAL",
+				},
+			}, nil)
+
+		gcpClientMock.
+			EXPECT().
+			GetProjectClusters(gomock.Any(), gomock.Eq(projects)).
+			Return([]*containerv1.Cluster{}, nil)
+
+		// act
+		suggestions, err := service.Suggest(ctx, filter, networkv1.Selector{}, 1, networkv1.SubnetSize{}, networkv1.TypeNode)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(suggestions))
+		assert.Equal(t, "172.28.32.0/21", suggestions[0].CIDR)
+	})
+}
+
+func TestNewAddressUsedRange(t *testing.T) {
+
+	t.Run("ReturnsRangeOfAddressWithPrefixLength", func(t *testing.T) {
+
+		address := &computev1.Address{
+			Name:         "google-managed-services-my-vpc",
+			Address:      "10.20.0.0",
+			PrefixLength: 16,
+			Purpose:      "VPC_PEERING",
+			Network:      "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/my-vpc",
+			SelfLink:     "https://www.googleapis.com/compute/v1/projects/my-project/global/addresses/google-managed-services-my-vpc",
+		}
+
+		// act
+		usedRange := newAddressUsedRange(address)
+
+		assert.Equal(t, "10.20.0.0/16", usedRange.CIDR)
+		assert.Equal(t, "address google-managed-services-my-vpc (VPC_PEERING) in project my-project in network my-vpc with cidr 10.20.0.0/16", usedRange.Describe())
+	})
+
+	t.Run("ReturnsSingleIPForAddressWithoutPrefixLength", func(t *testing.T) {
+
+		address := &computev1.Address{
+			Name:    "ilb",
+			Address: "10.0.0.10",
+			Purpose: "GCE_ENDPOINT",
+			Region:  "https://www.googleapis.com/compute/v1/projects/my-project/regions/europe-west4",
+		}
+
+		// act
+		usedRange := newAddressUsedRange(address)
+
+		assert.Equal(t, "10.0.0.10/32", usedRange.CIDR)
+		assert.Equal(t, "europe-west4", usedRange.Region)
+	})
+}
+
+func TestDedupeSubnetworks(t *testing.T) {
+
+	t.Run("RemovesSubnetworksWithSameSelfLink", func(t *testing.T) {
+
+		subnetworks := []*computev1.Subnetwork{
+			{Name: "a", SelfLink: "https://www.googleapis.com/compute/v1/projects/host-project/regions/europe-west4/subnetworks/a"},
+			{Name: "b", SelfLink: "https://www.googleapis.com/compute/v1/projects/host-project/regions/europe-west4/subnetworks/b"},
+			{Name: "a", SelfLink: "https://www.googleapis.com/compute/v1/projects/host-project/regions/europe-west4/subnetworks/a"},
+		}
+
+		// act
+		deduped := dedupeSubnetworks(subnetworks)
+
+		assert.Equal(t, 2, len(deduped))
+		assert.Equal(t, "a", deduped[0].Name)
+		assert.Equal(t, "b", deduped[1].Name)
+	})
+}
+
+func TestNewClusterUsedRanges(t *testing.T) {
+
+	t.Run("ReturnsMasterAndTPURangesOfVPCNativeCluster", func(t *testing.T) {
+
+		cluster := &containerv1.Cluster{
+			Name:     "my-cluster",
+			Location: "europe-west4",
+			Network:  "my-vpc",
+			SelfLink: "https://container.googleapis.com/v1/projects/my-project/locations/europe-west4/clusters/my-cluster",
+			PrivateClusterConfig: &containerv1.PrivateClusterConfig{
+				MasterIpv4CidrBlock: "172.16.0.16/28",
+			},
+			IpAllocationPolicy: &containerv1.IPAllocationPolicy{
+				UseIpAliases: true,
+			},
+			ClusterIpv4Cidr:  "10.4.0.0/14",
+			ServicesIpv4Cidr: "10.8.0.0/20",
+			TpuIpv4CidrBlock: "10.9.0.0/20",
+		}
+
+		// act
+		usedRanges := newClusterUsedRanges(cluster)
+
+		assert.Equal(t, 2, len(usedRanges))
+		assert.Equal(t, "cluster my-cluster/master in project my-project in region europe-west4 in network my-vpc with cidr 172.16.0.16/28", usedRanges[0].Describe())
+		assert.Equal(t, "10.9.0.0/20", usedRanges[1].CIDR)
+		assert.Equal(t, "my-cluster/tpu", usedRanges[1].Name)
+	})
+
+	t.Run("ReturnsPodAndServiceRangesOfRoutesBasedCluster", func(t *testing.T) {
+
+		cluster := &containerv1.Cluster{
+			Name:             "legacy-cluster",
+			ClusterIpv4Cidr:  "10.4.0.0/14",
+			ServicesIpv4Cidr: "10.8.0.0/20",
+		}
+
+		// act
+		usedRanges := newClusterUsedRanges(cluster)
+
+		assert.Equal(t, 2, len(usedRanges))
+		assert.Equal(t, "10.4.0.0/14", usedRanges[0].CIDR)
+		assert.Equal(t, "legacy-cluster/pods", usedRanges[0].Name)
+		assert.Equal(t, "10.8.0.0/20", usedRanges[1].CIDR)
+		assert.Equal(t, "legacy-cluster/services", usedRanges[1].Name)
+	})
+
+	t.Run("ReturnsRegionOfZonalCluster", func(t *testing.T) {
+
+		cluster := &containerv1.Cluster{
+			Name:     "zonal-cluster",
+			Location: "europe-west4-a",
+			PrivateClusterConfig: &containerv1.PrivateClusterConfig{
+				MasterIpv4CidrBlock: "172.16.0.16/28",
+			},
+		}
+
+		// act
+		usedRanges := newClusterUsedRanges(cluster)
+
+		assert.Equal(t, 1, len(usedRanges))
+		assert.Equal(t, "europe-west4", usedRanges[0].Region)
+	})
+}
+
+func TestNewPeeringRouteUsedRange(t *testing.T) {
+
+	t.Run("TagsRangeWithPeerNetwork", func(t *testing.T) {
+
+		peeringRoute := &gcp.PeeringRoute{
+			ExchangedPeeringRoute: &computev1.ExchangedPeeringRoute{
+				DestRange: "10.10.0.0/24",
+			},
+			Network:     "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/my-vpc",
+			PeeringName: "servicenetworking-googleapis-com",
+			PeerNetwork: "https://www.googleapis.com/compute/v1/projects/tenant-project/global/networks/servicenetworking",
+		}
+
+		// act
+		usedRange := newPeeringRouteUsedRange(peeringRoute)
+
+		assert.Equal(t, "tenant-project/servicenetworking", usedRange.PeerNetwork)
+		assert.Equal(t, "peering route servicenetworking-googleapis-com in project my-project in network my-vpc from peer network tenant-project/servicenetworking with cidr 10.10.0.0/24", usedRange.Describe())
+	})
+}
+
+func TestSuggestSingleNetworkRange(t *testing.T) {
+
+	t.Run("ReturnsRangeAccordingToAllocationStrategy", func(t *testing.T) {
+
+		usedRanges := []UsedRange{
+			{CIDR: "10.0.0.0/28", Source: UsedRangeSourceSuggestion},
+			{CIDR: "10.0.0.64/28", Source: UsedRangeSourceSuggestion},
+			{CIDR: "10.0.0.96/27", Source: UsedRangeSourceSuggestion},
+		}
+		usedRangesWithTopUsed := append([]UsedRange{{CIDR: "10.0.0.128/25", Source: UsedRangeSourceSuggestion}}, usedRanges...)
+
+		tests := []struct {
+			name                string
+			rangeConfigStrategy networkv1.AllocationStrategy
+			serviceStrategy     networkv1.AllocationStrategy
+			usedRanges          []UsedRange
+			expectedRange       string
+		}{
+			{"DefaultsToFirstFit", networkv1.AllocationStrategyDefault, networkv1.AllocationStrategyDefault, usedRanges, "10.0.0.16/28"},
+			{"FirstFitReturnsLowestFreeRange", networkv1.AllocationStrategyFirstFit, networkv1.AllocationStrategyDefault, usedRanges, "10.0.0.16/28"},
+			{"BestFitReturnsRangeFromSmallestFreeBlock", networkv1.AllocationStrategyBestFit, networkv1.AllocationStrategyDefault, usedRanges, "10.0.0.80/28"},
+			{"LastFitReturnsHighestFreeRange", networkv1.AllocationStrategyLastFit, networkv1.AllocationStrategyDefault, usedRanges, "10.0.0.240/28"},
+			{"LastFitSkipsUsedRangesTopDown", networkv1.AllocationStrategyLastFit, networkv1.AllocationStrategyDefault, usedRangesWithTopUsed, "10.0.0.80/28"},
+			{"ServiceStrategyOverridesRangeConfigStrategy", networkv1.AllocationStrategyBestFit, networkv1.AllocationStrategyLastFit, usedRanges, "10.0.0.240/28"},
+			{"ServiceStrategyAppliesToRangeConfigWithoutStrategy", networkv1.AllocationStrategyDefault, networkv1.AllocationStrategyBestFit, usedRanges, "10.0.0.80/28"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+				gcpClientMock := gcp.NewMockClient(ctrl)
+
+				ctx := context.Background()
+				service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json", WithAllocationStrategy(tt.serviceStrategy))
+
+				rangeConfigs := []networkv1.RangeConfig{
+					{
+						Type:               networkv1.TypeNode,
+						RangeType:          networkv1.RangeTypePrimary,
+						NetworkCIDR:        "10.0.0.0/24",
+						SubnetMask:         28,
+						AllocationStrategy: tt.rangeConfigStrategy,
+					},
+				}
+
+				// act
+				subnetRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, nil, nil, tt.usedRanges, networkv1.TypeNode, networkv1.Selector{}, networkv1.SubnetSize{})
+
+				assert.Nil(t, err)
+				assert.Equal(t, tt.expectedRange, subnetRange.String())
+			})
+		}
+	})
+
+	t.Run("ReturnsErrorIfAllRangesAreInUseForEveryAllocationStrategy", func(t *testing.T) {
+
+		tests := []struct {
+			name     string
+			strategy networkv1.AllocationStrategy
+		}{
+			{"FirstFit", networkv1.AllocationStrategyFirstFit},
+			{"BestFit", networkv1.AllocationStrategyBestFit},
+			{"LastFit", networkv1.AllocationStrategyLastFit},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+				gcpClientMock := gcp.NewMockClient(ctrl)
+
+				ctx := context.Background()
+				service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json", WithAllocationStrategy(tt.strategy))
+
+				rangeConfigs := []networkv1.RangeConfig{
+					{
+						Type:        networkv1.TypeNode,
+						RangeType:   networkv1.RangeTypePrimary,
+						NetworkCIDR: "10.0.0.0/24",
+						SubnetMask:  26,
+					},
+				}
+				usedRanges := []UsedRange{
+					{CIDR: "10.0.0.0/26", Source: UsedRangeSourceSuggestion},
+					{CIDR: "10.0.0.72/29", Source: UsedRangeSourceSuggestion},
+					{CIDR: "10.0.0.128/27", Source: UsedRangeSourceSuggestion},
+					{CIDR: "10.0.0.248/29", Source: UsedRangeSourceSuggestion},
+				}
+
+				// act
+				_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, nil, nil, usedRanges, networkv1.TypeNode, networkv1.Selector{}, networkv1.SubnetSize{})
+
+				assert.NotNil(t, err)
+				assert.Equal(t, "All of the possible 4 subnets of range 10.0.0.0/24 are already in use", err.Error())
+			})
+		}
+	})
+
+	t.Run("ReturnsAlignedRangesOfDifferentSizesFromSameNetwork", func(t *testing.T) {
 
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		gcpClientMock := gcp.NewMockClient(ctrl)
 
 		ctx := context.Background()
-		service, err := NewService(ctx, gcpClientMock, "./test-config.json")
-		filter := "labels.environment=dev"
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
 
-		projects := []*crmv1.Project{}
+		rangeConfigs := []networkv1.RangeConfig{
+			{
+				Type:          networkv1.TypePod,
+				RangeType:     networkv1.RangeTypeSecondary,
+				NetworkCIDR:   "10.0.0.0/9",
+				SubnetMask:    16,
+				MinSubnetMask: 14,
+				MaxSubnetMask: 20,
+			},
+		}
+		usedRanges := []UsedRange{
+			{
+				CIDR:   "10.0.0.0/20",
+				Source: UsedRangeSourceSuggestion,
+				Name:   "dev",
+			},
+		}
 
-		gcpClientMock.
-			EXPECT().
-			GetProjectByLabels(gomock.Any(), gomock.Any()).
-			Return(projects, nil)
+		// act
+		largeRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, nil, nil, usedRanges, networkv1.TypePod, networkv1.Selector{}, networkv1.SubnetSize{PrefixLength: 14})
+		assert.Nil(t, err)
+		smallRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, nil, nil, usedRanges, networkv1.TypePod, networkv1.Selector{}, networkv1.SubnetSize{PrefixLength: 20})
+		assert.Nil(t, err)
+		defaultRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, nil, nil, usedRanges, networkv1.TypePod, networkv1.Selector{}, networkv1.SubnetSize{})
+		assert.Nil(t, err)
 
-		gcpClientMock.
-			EXPECT().
-			GetProjectSubnetworks(gomock.Any(), gomock.Eq(projects)).
-			Return([]*computev1.Subnetwork{}, nil)
+		assert.Equal(t, "10.4.0.0/14", largeRange.String())
+		assert.Equal(t, "10.0.16.0/20", smallRange.String())
+		assert.Equal(t, "10.1.0.0/16", defaultRange.String())
+	})
 
-		gcpClientMock.
-			EXPECT().
-			GetProjectRoutes(gomock.Any(), gomock.Eq(projects)).
-			Return([]*computev1.Route{}, nil)
+	t.Run("ReturnsErrorIfRequestedSizeIsNotAllowed", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfigs := []networkv1.RangeConfig{
+			{
+				Type:        networkv1.TypePod,
+				RangeType:   networkv1.RangeTypeSecondary,
+				NetworkCIDR: "10.0.0.0/9",
+				SubnetMask:  16,
+			},
+		}
 
 		// act
-		_, err = service.Suggest(ctx, filter)
+		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, nil, nil, nil, networkv1.TypePod, networkv1.Selector{}, networkv1.SubnetSize{Nodes: 500, MaxPodsPerNode: 110})
 
-		assert.Nil(t, err)
+		assert.NotNil(t, err)
+		assert.Equal(t, "Requested size /15 for type pod is outside of the sizes /16 to /16 allowed for range 10.0.0.0/9", err.Error())
 	})
-}
-
-func TestSuggestSingleNetworkRange(t *testing.T) {
 
 	t.Run("ReturnsErrorWhenNoRangeConfigsMatchRegionAndNetworkType", func(t *testing.T) {
 
@@ -57,7 +1253,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		gcpClientMock := gcp.NewMockClient(ctrl)
 
 		ctx := context.Background()
-		service, err := NewService(ctx, gcpClientMock, "./test-config.json")
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
 
 		rangeConfigs := []networkv1.RangeConfig{}
 		subnetworks := []*computev1.Subnetwork{}
@@ -65,7 +1261,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		networkType := networkv1.TypeNode
 
 		// act
-		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, networkType)
+		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
 
 		assert.NotNil(t, err)
 		assert.Equal(t, "No ranges have been configured for type node, can't suggest a subnetwork range", err.Error())
@@ -78,7 +1274,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		gcpClientMock := gcp.NewMockClient(ctrl)
 
 		ctx := context.Background()
-		service, err := NewService(ctx, gcpClientMock, "./test-config.json")
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
 
 		rangeConfigs := []networkv1.RangeConfig{
 			{
@@ -99,12 +1295,127 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		networkType := networkv1.TypeNode
 
 		// act
-		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, networkType)
+		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
 
 		assert.NotNil(t, err)
 		assert.Equal(t, "Multiple ranges have been configured for type node, can't suggest a subnetwork range", err.Error())
 	})
 
+	t.Run("ReturnsRangeFromMostSpecificRangeConfigMatchingSelector", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfigs := []networkv1.RangeConfig{
+			{
+				Type:        networkv1.TypeNode,
+				RangeType:   networkv1.RangeTypePrimary,
+				NetworkCIDR: "172.28.0.0/14",
+				SubnetMask:  21,
+			},
+			{
+				Type:        networkv1.TypeNode,
+				RangeType:   networkv1.RangeTypePrimary,
+				NetworkCIDR: "172.16.0.0/14",
+				SubnetMask:  21,
+				Selector: networkv1.Selector{
+					Region: "europe-west1",
+				},
+			},
+			{
+				Type:        networkv1.TypeNode,
+				RangeType:   networkv1.RangeTypePrimary,
+				NetworkCIDR: "172.20.0.0/14",
+				SubnetMask:  21,
+				Selector: networkv1.Selector{
+					Region:      "europe-west1",
+					Environment: "prd",
+				},
+			},
+		}
+		subnetworks := []*computev1.Subnetwork{}
+		routes := []*computev1.Route{}
+		networkType := networkv1.TypeNode
+
+		// act
+		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{Region: "europe-west1", Environment: "prd"}, networkv1.SubnetSize{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "172.20.0.0/21", subnetworkRange.String())
+	})
+
+	t.Run("ReturnsRangeFromRangeConfigWithoutSelectorIfNoneMatchSelector", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfigs := []networkv1.RangeConfig{
+			{
+				Type:        networkv1.TypeNode,
+				RangeType:   networkv1.RangeTypePrimary,
+				NetworkCIDR: "172.28.0.0/14",
+				SubnetMask:  21,
+			},
+			{
+				Type:        networkv1.TypeNode,
+				RangeType:   networkv1.RangeTypePrimary,
+				NetworkCIDR: "172.16.0.0/14",
+				SubnetMask:  21,
+				Selector: networkv1.Selector{
+					Region: "europe-west1",
+				},
+			},
+		}
+		subnetworks := []*computev1.Subnetwork{}
+		routes := []*computev1.Route{}
+		networkType := networkv1.TypeNode
+
+		// act
+		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{Region: "europe-west4"}, networkv1.SubnetSize{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "172.28.0.0/21", subnetworkRange.String())
+	})
+
+	t.Run("ReturnsErrorWhenNoRangeConfigsMatchSelector", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfigs := []networkv1.RangeConfig{
+			{
+				Type:        networkv1.TypeNode,
+				RangeType:   networkv1.RangeTypePrimary,
+				NetworkCIDR: "172.16.0.0/14",
+				SubnetMask:  21,
+				Selector: networkv1.Selector{
+					Region: "europe-west1",
+				},
+			},
+		}
+		subnetworks := []*computev1.Subnetwork{}
+		routes := []*computev1.Route{}
+		networkType := networkv1.TypeNode
+
+		// act
+		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{Region: "europe-west4", Environment: "dev"}, networkv1.SubnetSize{})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "No ranges have been configured for type node in region europe-west4 in environment dev, can't suggest a subnetwork range", err.Error())
+	})
+
 	t.Run("ReturnsErrorWhenMoreOneRangeConfigMatchesAndAllPossibleSubnetsAreInUseBySubnets", func(t *testing.T) {
 
 		ctrl := gomock.NewController(t)
@@ -112,7 +1423,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		gcpClientMock := gcp.NewMockClient(ctrl)
 
 		ctx := context.Background()
-		service, err := NewService(ctx, gcpClientMock, "./test-config.json")
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
 
 		rangeConfigs := []networkv1.RangeConfig{
 			{
@@ -136,7 +1447,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		networkType := networkv1.TypeNode
 
 		// act
-		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, networkType)
+		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
 
 		assert.NotNil(t, err)
 		assert.Equal(t, "All of the possible 2 subnets of range 172.28.0.0/14 are already in use", err.Error())
@@ -149,7 +1460,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		gcpClientMock := gcp.NewMockClient(ctrl)
 
 		ctx := context.Background()
-		service, err := NewService(ctx, gcpClientMock, "./test-config.json")
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
 
 		rangeConfigs := []networkv1.RangeConfig{
 			{
@@ -169,7 +1480,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		networkType := networkv1.TypeNode
 
 		// act
-		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, networkType)
+		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
 
 		assert.Nil(t, err)
 		assert.Equal(t, "172.30.0.0/15", subnetworkRange.String())
@@ -182,7 +1493,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		gcpClientMock := gcp.NewMockClient(ctrl)
 
 		ctx := context.Background()
-		service, err := NewService(ctx, gcpClientMock, "./test-config.json")
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
 
 		rangeConfigs := []networkv1.RangeConfig{
 			{
@@ -207,7 +1518,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		networkType := networkv1.TypePod
 
 		// act
-		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, networkType)
+		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
 
 		assert.Nil(t, err)
 		assert.Equal(t, "10.1.0.0/16", subnetworkRange.String())
@@ -220,7 +1531,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		gcpClientMock := gcp.NewMockClient(ctrl)
 
 		ctx := context.Background()
-		service, err := NewService(ctx, gcpClientMock, "./test-config.json")
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
 
 		rangeConfigs := []networkv1.RangeConfig{
 			{
@@ -242,7 +1553,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		networkType := networkv1.TypeNode
 
 		// act
-		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, networkType)
+		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
 
 		assert.NotNil(t, err)
 		assert.Equal(t, "All of the possible 2 subnets of range 172.28.0.0/14 are already in use", err.Error())
@@ -255,7 +1566,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		gcpClientMock := gcp.NewMockClient(ctrl)
 
 		ctx := context.Background()
-		service, err := NewService(ctx, gcpClientMock, "./test-config.json")
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
 
 		rangeConfigs := []networkv1.RangeConfig{
 			{
@@ -274,7 +1585,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		networkType := networkv1.TypeNode
 
 		// act
-		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, networkType)
+		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
 
 		assert.Nil(t, err)
 		assert.NotNil(t, subnetworkRange)
@@ -288,7 +1599,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		gcpClientMock := gcp.NewMockClient(ctrl)
 
 		ctx := context.Background()
-		service, err := NewService(ctx, gcpClientMock, "./test-config.json")
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
 
 		rangeConfigs := []networkv1.RangeConfig{
 			{
@@ -303,7 +1614,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		networkType := networkv1.TypeNode
 
 		// act
-		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, networkType)
+		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
 
 		assert.Nil(t, err)
 		assert.NotNil(t, subnetworkRange)
@@ -317,7 +1628,7 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		gcpClientMock := gcp.NewMockClient(ctrl)
 
 		ctx := context.Background()
-		service, err := NewService(ctx, gcpClientMock, "./test-config.json")
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
 
 		rangeConfigs := []networkv1.RangeConfig{
 			{
@@ -336,10 +1647,118 @@ func TestSuggestSingleNetworkRange(t *testing.T) {
 		networkType := networkv1.TypeNode
 
 		// act
-		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, networkType)
+		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
 
 		assert.Nil(t, err)
 		assert.NotNil(t, subnetworkRange)
 		assert.Equal(t, "172.28.0.0/15", subnetworkRange.String())
 	})
+
+	t.Run("ReturnsFirstAvailableRangeIfSomeOfThemAreInUseByUsedRanges", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfigs := []networkv1.RangeConfig{
+			{
+				Type:        networkv1.TypeNode,
+				RangeType:   networkv1.RangeTypePrimary,
+				NetworkCIDR: "172.28.0.0/14",
+				SubnetMask:  15,
+			},
+		}
+		subnetworks := []*computev1.Subnetwork{}
+		routes := []*computev1.Route{}
+		usedRanges := []UsedRange{
+			{
+				CIDR:   "172.28.0.0/15",
+				Source: UsedRangeSourceSuggestion,
+				Name:   "node-1",
+			},
+		}
+		networkType := networkv1.TypeNode
+
+		// act
+		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, usedRanges, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
+
+		assert.Nil(t, err)
+		assert.NotNil(t, subnetworkRange)
+		assert.Equal(t, "172.30.0.0/15", subnetworkRange.String())
+	})
+
+	t.Run("ReturnsFirstAvailableIPv6RangeIfSomeOfThemAreInUseBySubnetIPv6Ranges", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfigs := []networkv1.RangeConfig{
+			{
+				Type:        networkv1.TypePod,
+				RangeType:   networkv1.RangeTypeSecondary,
+				NetworkCIDR: "fd12:3456:789a::/48",
+				SubnetMask:  64,
+			},
+		}
+		subnetworks := []*computev1.Subnetwork{
+			{
+				IpCidrRange:   "172.28.0.0/21",
+				Ipv6CidrRange: "fd12:3456:789a::/64",
+				Region:        "https://www.googleapis.com/compute/v1/projects/project-id/regions/europe-west1",
+			},
+		}
+		routes := []*computev1.Route{
+			{
+				DestRange: "::/0",
+			},
+			{
+				DestRange: "fd12:3456:789a:1::/64",
+			},
+		}
+		networkType := networkv1.TypePod
+
+		// act
+		subnetworkRange, err := service.SuggestSingleNetworkRange(ctx, rangeConfigs, subnetworks, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "fd12:3456:789a:2::/64", subnetworkRange.String())
+	})
+
+	t.Run("ReturnsErrorWithTotalNumberOfSubnetsIfAllIPv6RangesAreInUse", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfigs := []networkv1.RangeConfig{
+			{
+				Type:        networkv1.TypePod,
+				RangeType:   networkv1.RangeTypeSecondary,
+				NetworkCIDR: "fd12:3456:789a::/48",
+				SubnetMask:  112,
+			},
+		}
+		routes := []*computev1.Route{
+			{
+				DestRange: "fd12:3456:789a::/48",
+			},
+		}
+		networkType := networkv1.TypePod
+
+		// act
+		_, err = service.SuggestSingleNetworkRange(ctx, rangeConfigs, nil, routes, nil, networkType, networkv1.Selector{}, networkv1.SubnetSize{})
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "All of the possible 18446744073709551616 subnets of range fd12:3456:789a::/48 are already in use", err.Error())
+	})
 }
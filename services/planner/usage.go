@@ -0,0 +1,123 @@
+package planner
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"sort"
+
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/rs/zerolog/log"
+	computev1 "google.golang.org/api/compute/v1"
+)
+
+// RangeConfigUsage describes how many of the subnet slots of a range config are in use; fragmented slots are used only partially, so they can't be suggested while still having free addresses
+type RangeConfigUsage struct {
+	RangeConfig     networkv1.RangeConfig `json:"range_config" yaml:"range_config"`
+	TotalSlots      *big.Int              `json:"total_slots" yaml:"total_slots"`
+	UsedSlots       *big.Int              `json:"used_slots" yaml:"used_slots"`
+	FreeSlots       *big.Int              `json:"free_slots" yaml:"free_slots"`
+	FragmentedSlots *big.Int              `json:"fragmented_slots" yaml:"fragmented_slots"`
+	PercentageUsed  float64               `json:"percentage_used" yaml:"percentage_used"`
+	Projects        []ProjectUsage        `json:"projects" yaml:"projects"`
+}
+
+// ProjectUsage is the number of subnet slots of a range config used by a single project
+type ProjectUsage struct {
+	Project   string   `json:"project" yaml:"project"`
+	UsedSlots *big.Int `json:"used_slots" yaml:"used_slots"`
+}
+
+func (s *service) Usage(ctx context.Context, filter ProjectFilter) (usages []RangeConfigUsage, err error) {
+
+	config, err := s.getValidConfig(ctx)
+	if err != nil {
+		return
+	}
+
+	subnetworks, routes, usedRanges, err := s.getUsedResources(ctx, filter)
+	if err != nil {
+		return
+	}
+
+	usages = []RangeConfigUsage{}
+	for _, rc := range config.RangeConfigs {
+		usage, err := s.UsageSingleRangeConfig(ctx, rc, subnetworks, routes, usedRanges)
+		if err != nil {
+			return usages, err
+		}
+
+		usages = append(usages, usage)
+	}
+
+	return
+}
+
+func (s *service) UsageSingleRangeConfig(ctx context.Context, rangeConfig networkv1.RangeConfig, subnetworks []*computev1.Subnetwork, routes []*computev1.Route, usedRanges []UsedRange) (usage RangeConfigUsage, err error) {
+
+	log.Debug().Msgf("Calculating usage of range %v for network type %v (with %v subnetworks and %v routes and %v used ranges)...", rangeConfig.NetworkCIDR, rangeConfig.Type, len(subnetworks), len(routes), len(usedRanges))
+
+	applicableUsedRanges, err := s.getApplicableUsedRanges(rangeConfig, subnetworks, routes, usedRanges)
+	if err != nil {
+		return
+	}
+
+	index, err := newUsedRangeIndex(applicableUsedRanges)
+	if err != nil {
+		return
+	}
+
+	_, networkIPnet, err := net.ParseCIDR(rangeConfig.NetworkCIDR)
+	if err != nil {
+		return
+	}
+
+	total, used, fragmented := index.slotUsage(networkIPnet, rangeConfig.SubnetMask)
+
+	usage = RangeConfigUsage{
+		RangeConfig:     rangeConfig,
+		TotalSlots:      total,
+		UsedSlots:       used,
+		FreeSlots:       new(big.Int).Sub(total, used),
+		FragmentedSlots: fragmented,
+		Projects:        []ProjectUsage{},
+	}
+
+	if total.Sign() > 0 {
+		percentage := new(big.Float).Quo(new(big.Float).SetInt(used), new(big.Float).SetInt(total))
+		usage.PercentageUsed, _ = percentage.Mul(percentage, big.NewFloat(100)).Float64()
+	}
+
+	// count slots per project with an index per project, so a slot used by multiple ranges of the same project is counted once
+	usedRangesPerProject := map[string][]UsedRange{}
+	for _, ur := range applicableUsedRanges {
+		if ur.Project == "" {
+			continue
+		}
+		usedRangesPerProject[ur.Project] = append(usedRangesPerProject[ur.Project], ur)
+	}
+
+	for project, projectUsedRanges := range usedRangesPerProject {
+		projectIndex, err := newUsedRangeIndex(projectUsedRanges)
+		if err != nil {
+			return usage, err
+		}
+
+		_, projectUsed, _ := projectIndex.slotUsage(networkIPnet, rangeConfig.SubnetMask)
+		usage.Projects = append(usage.Projects, ProjectUsage{
+			Project:   project,
+			UsedSlots: projectUsed,
+		})
+	}
+
+	// sort projects by most used slots first
+	sort.Slice(usage.Projects, func(i, j int) bool {
+		cmp := usage.Projects[i].UsedSlots.Cmp(usage.Projects[j].UsedSlots)
+		if cmp != 0 {
+			return cmp > 0
+		}
+		return usage.Projects[i].Project < usage.Projects[j].Project
+	})
+
+	return
+}
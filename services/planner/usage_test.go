@@ -0,0 +1,98 @@
+package planner
+
+import (
+	"context"
+	"testing"
+
+	networkv1 "github.com/estafette/estafette-gcp-network-planner/api/network/v1"
+	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	computev1 "google.golang.org/api/compute/v1"
+)
+
+func TestUsageSingleRangeConfig(t *testing.T) {
+
+	t.Run("ReturnsSlotCountsAndProjectsUsingTheMostSlotsFirst", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfig := networkv1.RangeConfig{
+			Type:        networkv1.TypeNode,
+			RangeType:   networkv1.RangeTypePrimary,
+			NetworkCIDR: "172.28.0.0/14",
+			SubnetMask:  16,
+		}
+		subnetworks := []*computev1.Subnetwork{
+			{
+				Name:        "small",
+				IpCidrRange: "172.28.0.0/20",
+				SelfLink:    "https://www.googleapis.com/compute/v1/projects/project-a/regions/europe-west1/subnetworks/small",
+			},
+			{
+				Name:        "large",
+				IpCidrRange: "172.29.0.0/16",
+				SelfLink:    "https://www.googleapis.com/compute/v1/projects/project-b/regions/europe-west1/subnetworks/large",
+			},
+			{
+				Name:        "larger",
+				IpCidrRange: "172.30.0.0/16",
+				SelfLink:    "https://www.googleapis.com/compute/v1/projects/project-b/regions/europe-west1/subnetworks/larger",
+			},
+		}
+		usedRanges := []UsedRange{
+			{
+				CIDR:   "172.31.0.0/24",
+				Source: UsedRangeSourceReservation,
+				Name:   "reserved by someone",
+			},
+		}
+
+		// act
+		usage, err := service.UsageSingleRangeConfig(ctx, rangeConfig, subnetworks, nil, usedRanges)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), usage.TotalSlots.Int64())
+		assert.Equal(t, int64(4), usage.UsedSlots.Int64())
+		assert.Equal(t, int64(0), usage.FreeSlots.Int64())
+		assert.Equal(t, int64(2), usage.FragmentedSlots.Int64())
+		assert.Equal(t, float64(100), usage.PercentageUsed)
+		assert.Equal(t, 2, len(usage.Projects))
+		assert.Equal(t, "project-b", usage.Projects[0].Project)
+		assert.Equal(t, int64(2), usage.Projects[0].UsedSlots.Int64())
+		assert.Equal(t, "project-a", usage.Projects[1].Project)
+		assert.Equal(t, int64(1), usage.Projects[1].UsedSlots.Int64())
+	})
+
+	t.Run("ReturnsAllSlotsFreeIfNothingIsUsed", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		gcpClientMock := gcp.NewMockClient(ctrl)
+
+		ctx := context.Background()
+		service, err := NewService(ctx, gcpClientMock, nil, "./test-config.json")
+
+		rangeConfig := networkv1.RangeConfig{
+			Type:        networkv1.TypeNode,
+			RangeType:   networkv1.RangeTypePrimary,
+			NetworkCIDR: "172.28.0.0/14",
+			SubnetMask:  20,
+		}
+
+		// act
+		usage, err := service.UsageSingleRangeConfig(ctx, rangeConfig, nil, nil, nil)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(64), usage.TotalSlots.Int64())
+		assert.Equal(t, int64(0), usage.UsedSlots.Int64())
+		assert.Equal(t, int64(64), usage.FreeSlots.Int64())
+		assert.Equal(t, float64(0), usage.PercentageUsed)
+		assert.Equal(t, 0, len(usage.Projects))
+	})
+}
@@ -0,0 +1,172 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/estafette/estafette-gcp-network-planner/clients/gcp"
+	computev1 "google.golang.org/api/compute/v1"
+	containerv1 "google.golang.org/api/container/v1"
+)
+
+const (
+	UsedRangeSourceSubnetwork     = "subnetwork"
+	UsedRangeSourceSecondaryRange = "secondary range"
+	UsedRangeSourceRoute          = "route"
+	UsedRangeSourcePeeringRoute   = "peering route"
+	UsedRangeSourceAddress        = "address"
+	UsedRangeSourceCluster        = "cluster"
+	UsedRangeSourceReservation    = "reservation"
+	UsedRangeSourceSuggestion     = "suggestion"
+)
+
+// UsedRange is a network range that's occupied, together with the resource occupying it
+type UsedRange struct {
+	CIDR    string `json:"cidr" yaml:"cidr"`
+	Source  string `json:"source" yaml:"source"`
+	Name    string `json:"name,omitempty" yaml:"name,omitempty"`
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+	Region  string `json:"region,omitempty" yaml:"region,omitempty"`
+	Network string `json:"network,omitempty" yaml:"network,omitempty"`
+
+	// PeerNetwork is the network a peering route is imported from, as project/network
+	PeerNetwork string `json:"peer_network,omitempty" yaml:"peer_network,omitempty"`
+}
+
+// Describe returns a human readable description of what occupies the range
+func (ur UsedRange) Describe() string {
+	description := ur.Source
+	if ur.Name != "" {
+		description += " " + ur.Name
+	}
+	if ur.Project != "" {
+		description += fmt.Sprintf(" in project %v", ur.Project)
+	}
+	if ur.Region != "" {
+		description += fmt.Sprintf(" in region %v", ur.Region)
+	}
+	if ur.Network != "" {
+		description += fmt.Sprintf(" in network %v", ur.Network)
+	}
+	if ur.PeerNetwork != "" {
+		description += fmt.Sprintf(" from peer network %v", ur.PeerNetwork)
+	}
+
+	return description + fmt.Sprintf(" with cidr %v", ur.CIDR)
+}
+
+func newSubnetworkUsedRange(sn *computev1.Subnetwork, cidr, source, rangeName string) UsedRange {
+	name := sn.Name
+	if rangeName != "" {
+		name += "/" + rangeName
+	}
+
+	return UsedRange{
+		CIDR:    cidr,
+		Source:  source,
+		Name:    name,
+		Project: gcp.GetProjectFromSelfLink(sn.SelfLink),
+		Region:  getLastURLSegment(sn.Region),
+		Network: getLastURLSegment(sn.Network),
+	}
+}
+
+func newRouteUsedRange(r *computev1.Route) UsedRange {
+	return UsedRange{
+		CIDR:    r.DestRange,
+		Source:  UsedRangeSourceRoute,
+		Name:    r.Name,
+		Project: gcp.GetProjectFromSelfLink(r.SelfLink),
+		Network: getLastURLSegment(r.Network),
+	}
+}
+
+// isDefaultRoute returns true for the ipv4 and ipv6 default routes, which cover every range and therefore can't be treated as used
+func isDefaultRoute(destRange string) bool {
+	return destRange == "0.0.0.0/0" || destRange == "::/0"
+}
+
+func newPeeringRouteUsedRange(r *gcp.PeeringRoute) UsedRange {
+	return UsedRange{
+		CIDR:        r.DestRange,
+		Source:      UsedRangeSourcePeeringRoute,
+		Name:        r.PeeringName,
+		Project:     gcp.GetProjectFromSelfLink(r.Network),
+		Region:      r.NextHopRegion,
+		Network:     getLastURLSegment(r.Network),
+		PeerNetwork: gcp.GetProjectFromSelfLink(r.PeerNetwork) + "/" + getLastURLSegment(r.PeerNetwork),
+	}
+}
+
+// newAddressUsedRange returns the range of an internal address; addresses without prefix length occupy a single ip
+func newAddressUsedRange(a *computev1.Address) UsedRange {
+	prefixLength := a.PrefixLength
+	if prefixLength == 0 {
+		prefixLength = 32
+		if a.IpVersion == "IPV6" || strings.Contains(a.Address, ":") {
+			prefixLength = 128
+		}
+	}
+
+	name := a.Name
+	if a.Purpose != "" {
+		name += fmt.Sprintf(" (%v)", a.Purpose)
+	}
+
+	return UsedRange{
+		CIDR:    fmt.Sprintf("%v/%v", a.Address, prefixLength),
+		Source:  UsedRangeSourceAddress,
+		Name:    name,
+		Project: gcp.GetProjectFromSelfLink(a.SelfLink),
+		Region:  getLastURLSegment(a.Region),
+		Network: getLastURLSegment(a.Network),
+	}
+}
+
+// newClusterUsedRanges returns the ranges of a gke cluster that don't live on a subnetwork: the control plane range of private clusters, the pod and service ranges of routes-based clusters and the tpu range
+func newClusterUsedRanges(c *containerv1.Cluster) (usedRanges []UsedRange) {
+	newUsedRange := func(cidr, rangeName string) UsedRange {
+		return UsedRange{
+			CIDR:    cidr,
+			Source:  UsedRangeSourceCluster,
+			Name:    c.Name + "/" + rangeName,
+			Project: gcp.GetProjectFromSelfLink(c.SelfLink),
+			Region:  getRegionFromLocation(c.Location),
+			Network: getLastURLSegment(c.Network),
+		}
+	}
+
+	if c.PrivateClusterConfig != nil && c.PrivateClusterConfig.MasterIpv4CidrBlock != "" {
+		usedRanges = append(usedRanges, newUsedRange(c.PrivateClusterConfig.MasterIpv4CidrBlock, "master"))
+	}
+
+	// vpc-native clusters use secondary ranges of their subnetwork for pods and services, which are already taken into account
+	if c.IpAllocationPolicy == nil || !c.IpAllocationPolicy.UseIpAliases {
+		if c.ClusterIpv4Cidr != "" {
+			usedRanges = append(usedRanges, newUsedRange(c.ClusterIpv4Cidr, "pods"))
+		}
+		if c.ServicesIpv4Cidr != "" {
+			usedRanges = append(usedRanges, newUsedRange(c.ServicesIpv4Cidr, "services"))
+		}
+	}
+
+	if c.TpuIpv4CidrBlock != "" {
+		usedRanges = append(usedRanges, newUsedRange(c.TpuIpv4CidrBlock, "tpu"))
+	}
+
+	return
+}
+
+// getRegionFromLocation returns the region of a gke location, which is a zone like europe-west4-a for zonal clusters
+func getRegionFromLocation(location string) string {
+	parts := strings.Split(location, "-")
+	if len(parts) == 3 {
+		return strings.Join(parts[:2], "-")
+	}
+
+	return location
+}
+
+func getLastURLSegment(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}